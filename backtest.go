@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/config"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const CMDBacktest = "backtest"
+
+// backtest reports the notifications a watcher config would have sent over
+// the recorded floor price history. With -watcher, the collection, rarity,
+// threshold and currency flags that are given override the watcher's config:
+//
+//	bitverse-nft-bot backtest -rarity Common -threshold 200 -window 720h
+//	bitverse-nft-bot backtest -watcher common -threshold 225
+func backtest(args []string) error {
+	fs := flag.NewFlagSet(CMDBacktest, flag.ExitOnError)
+	name := fs.String("watcher", "", "use the config of this watcher from the watchers file")
+	collection := fs.String("collection", data.CollectionHero, "collection to watch")
+	rarity := fs.String("rarity", "", "comma-separated rarities to watch (default all)")
+	threshold := fs.Float64("threshold", 0, "fiat threshold")
+	currency := fs.String("currency", string(coinbase.FiatUSD), "currency of the threshold")
+	window := fs.Duration("window", 30*24*time.Hour, "how far back in the history to start")
+	historyFile := fs.String("history", config.DataPath(history.DefaultHistoryFile), "price history file")
+	fs.Parse(args)
+
+	// Without a watcher every flag applies, defaults included.
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	apply := func(key string) bool { return *name == "" || given[key] }
+
+	cfg := notifier.WatcherConfig{Name: CMDBacktest}
+	if *name != "" {
+		cfgs, err := notifier.LoadWatcherConfigs()
+		if err != nil {
+			return err
+		}
+
+		var found bool
+		for _, c := range cfgs {
+			if c.Name == *name {
+				cfg, found = c, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no watcher named %v", *name)
+		}
+	}
+
+	if apply("collection") {
+		cfg.Collection = *collection
+	}
+	if apply("rarity") {
+		cfg.Rarity = nil
+		if *rarity != "" {
+			cfg.Rarity = strings.Split(*rarity, ",")
+		}
+	}
+	if apply("threshold") {
+		cfg.Threshold = *threshold
+	}
+	if apply("currency") {
+		cfg.Currency = coinbase.FiatSymbol(*currency)
+	}
+
+	store, err := history.NewFileStore(*historyFile, history.DefaultRetention)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-*window)
+	result, err := notifier.Backtest(cfg, store, since)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v checks since %v at or below %v\n", result.Checks, since.Format(time.RFC3339), handlers.FormatPrice(cfg.Threshold, cfg.Currency))
+	if result.Lowest != nil {
+		l := result.Lowest
+		fmt.Printf("Lowest floor: %v (%v %v) for %v #%v at %v\n", handlers.FormatPrice(l.FiatPrice, cfg.Currency), l.Price, l.Symbol, l.Rarity, l.TokenID, l.At.Format(time.RFC3339))
+	}
+
+	fmt.Printf("%v notifications would have been sent\n", len(result.Notifications))
+	if len(result.Notifications) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tRARITY\tTOKEN\tPRICE\tFIAT")
+	for _, n := range result.Notifications {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v %v\t%v\n", n.At.Format(time.RFC3339), n.Rarity, n.TokenID, n.Price, n.Symbol, handlers.FormatPrice(n.FiatPrice, cfg.Currency))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,31 @@
+// Command mockserver serves canned Immutable X and Coinbase responses for
+// running the bot without network access:
+//
+//	go run ./cmd/mockserver -fixtures testdata/mockserver
+//	BITVERSE_NFT_BOT_IMX_API_URL=http://localhost:8080 \
+//	BITVERSE_NFT_BOT_COINBASE_API_URL=http://localhost:8080 go run .
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/mockserver"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	dir := flag.String("fixtures", "testdata/mockserver", "directory with orders.json, assets.json and spot.json")
+	flag.Parse()
+
+	log.SetLevel(log.DebugLevel)
+
+	fixtures, err := mockserver.LoadFixtures(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Infof("serving %v orders, %v assets and %v spot prices on %v", len(fixtures.Orders), len(fixtures.Assets), len(fixtures.Spot), *addr)
+	log.Fatal(http.ListenAndServe(*addr, mockserver.New(fixtures)))
+}
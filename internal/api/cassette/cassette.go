@@ -0,0 +1,195 @@
+// Package cassette records the Immutable X and Coinbase calls made through an
+// api.ClientsManager to a file, and replays them later without the network.
+//
+// Calls are matched on their name and request, ignoring the timestamps
+// watchers use to poll for new orders. Repeated identical calls get the
+// recorded responses in order, and the last one once those run out, so a
+// replayed watcher sees the market evolve as it did while recording.
+package cassette
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+
+	// MaxLineSize bounds a single recorded interaction when replaying.
+	MaxLineSize = 64 * 1024 * 1024
+)
+
+var ErrNotRecorded = errors.New("call not found in cassette")
+
+// Interaction is one call, stored as a JSON line in the cassette file.
+type Interaction struct {
+	At       time.Time       `json:"at"`
+	Call     string          `json:"call"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (in Interaction) key() string {
+	return in.Call + " " + string(in.Request)
+}
+
+type Cassette struct {
+	file         *os.File
+	mode         string
+	interactions map[string][]Interaction
+	played       map[string]int
+	mu           sync.Mutex
+}
+
+// Open starts recording to a new file, or loads it for replay. Recording
+// refuses an existing file, since appending would merge separate recordings
+// into a single sequence of responses per call.
+func Open(path, mode string) (*Cassette, error) {
+	c := &Cassette{
+		mode:         mode,
+		interactions: make(map[string][]Interaction),
+		played:       make(map[string]int),
+	}
+
+	switch mode {
+	case "", ModeRecord:
+		c.mode = ModeRecord
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("cassette %v already exists, remove it to record again", path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.file = f
+		log.Infof("recording API calls to cassette %v", path)
+
+	case ModeReplay:
+		if err := c.load(path); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %v", mode)
+	}
+
+	return c, nil
+}
+
+func (c *Cassette) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, MaxLineSize)
+
+	var n int
+	for scanner.Scan() {
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return fmt.Errorf("could not parse cassette %v line %d: %w", path, n+1, err)
+		}
+
+		c.interactions[in.key()] = append(c.interactions[in.key()], in)
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("replaying %v API calls from cassette %v", n, path)
+	return nil
+}
+
+func (c *Cassette) Replaying() bool {
+	return c.mode == ModeReplay
+}
+
+func (c *Cassette) Close() error {
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Close()
+}
+
+// record appends the call and its outcome to the cassette.
+func (c *Cassette) record(call string, req, resp interface{}, callErr error) {
+	in := Interaction{At: time.Now(), Call: call}
+
+	var err error
+	if in.Request, err = json.Marshal(req); err != nil {
+		log.Errorf("could not record %v request: %v", call, err)
+		return
+	}
+
+	if callErr != nil {
+		in.Error = callErr.Error()
+	} else if in.Response, err = json.Marshal(resp); err != nil {
+		log.Errorf("could not record %v response: %v", call, err)
+		return
+	}
+
+	line, err := json.Marshal(in)
+	if err != nil {
+		log.Errorf("could not record %v: %v", call, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		log.Errorf("could not write to cassette: %v", err)
+	}
+}
+
+// replay decodes the next recorded response to the call into resp and
+// returns the recorded error, if any.
+func (c *Cassette) replay(call string, req, resp interface{}) error {
+	request, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	key := Interaction{Call: call, Request: request}.key()
+
+	c.mu.Lock()
+	recorded := c.interactions[key]
+	if len(recorded) == 0 {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %v %s", ErrNotRecorded, call, request)
+	}
+
+	i := c.played[key]
+	if i >= len(recorded) {
+		i = len(recorded) - 1
+	} else {
+		c.played[key]++
+	}
+	in := recorded[i]
+	c.mu.Unlock()
+
+	if in.Error != "" {
+		return errors.New(in.Error)
+	}
+
+	return json.Unmarshal(in.Response, resp)
+}
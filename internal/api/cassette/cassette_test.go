@@ -0,0 +1,249 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	heroes = "0xheroes"
+	owner  = "0x1111111111111111111111111111111111111111"
+)
+
+var start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func pollConfig(since time.Time) *orders.ListOrdersConfig {
+	return &orders.ListOrdersConfig{
+		SellTokenAddress:    heroes,
+		Status:              "active",
+		MinTimestamp:        since.Format(time.RFC3339),
+		UpdatedMinTimestamp: since.Format(time.RFC3339),
+	}
+}
+
+func orderIDs(result []imxapi.Order) []int32 {
+	ids := []int32{}
+	for _, order := range result {
+		ids = append(ids, order.OrderId)
+	}
+
+	return ids
+}
+
+// record polls the fake orders twice, a new order arriving in between, and
+// makes one call of every other kind.
+func record(t *testing.T, path string) {
+	t.Helper()
+
+	list := fake.NewOrders(nil, fake.Order(1, heroes, "1", 0.1, start))
+	assetList := fake.NewAssets(fake.Asset(heroes, "1", owner, nil))
+	spot := fake.NewSpotPrices()
+	spot.Set(coinbase.CryptoETH, coinbase.FiatUSD, 2000)
+	cm := fake.NewClientsManager(list, assetList, spot)
+
+	c, err := Open(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	Wrap(cm, c)
+
+	ctx := context.Background()
+	if _, err := cm.OrdersClient.ListOrders(ctx, pollConfig(start)); err != nil {
+		t.Fatal(err)
+	}
+	list.Put(fake.Order(2, heroes, "2", 0.05, start.Add(time.Minute)))
+	if _, err := cm.OrdersClient.ListOrders(ctx, pollConfig(start.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.OrdersPager.ListOrdersPage(ctx, &orders.ListOrdersConfig{SellTokenAddress: heroes, PageSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.AssetsClient.GetAsset(ctx, heroes, "1", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.AssetsClient.ListAssets(ctx, &assets.ListAssetsConfig{Collection: heroes}); err != nil {
+		t.Fatal(err)
+	}
+	cm.SpotPriceClient.RetrieveSpotPrice(coinbase.CryptoETH, coinbase.FiatUSD)
+
+	assetList.Err = errors.New("service unavailable")
+	if _, err := cm.AssetsClient.GetAsset(ctx, heroes, "2", true); err == nil {
+		t.Fatal("got no error from the failing fake")
+	}
+}
+
+// replayClients wraps empty fakes, so every answer comes from the cassette.
+func replayClients(t *testing.T, path string) *api.ClientsManager {
+	t.Helper()
+
+	c, err := Open(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	cm := fake.NewClientsManager(nil, nil, nil)
+	Wrap(cm, c)
+	return cm
+}
+
+func TestReplayPollsInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	record(t, path)
+	cm := replayClients(t, path)
+
+	// The timestamps differ from the recording, the polls still match, and
+	// the last response, the order new since the first poll, repeats once
+	// the recorded ones run out.
+	want := [][]int32{{1}, {2}, {2}}
+	for i, w := range want {
+		result, err := cm.OrdersClient.ListOrders(context.Background(), pollConfig(time.Now().Add(time.Duration(i)*time.Hour)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := orderIDs(result); !reflect.DeepEqual(got, w) {
+			t.Errorf("poll %v got orders %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestReplayEveryCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	record(t, path)
+	cm := replayClients(t, path)
+	ctx := context.Background()
+
+	page, err := cm.OrdersPager.ListOrdersPage(ctx, &orders.ListOrdersConfig{SellTokenAddress: heroes, PageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := orderIDs(page.Result); !reflect.DeepEqual(got, []int32{1}) || page.Cursor == "" {
+		t.Errorf("got page %v with cursor %q, want order 1 and a cursor", got, page.Cursor)
+	}
+
+	asset, err := cm.AssetsClient.GetAsset(ctx, heroes, "1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.TokenId != "1" || asset.GetUser() != owner {
+		t.Errorf("got asset %v owned by %v", asset.TokenId, asset.GetUser())
+	}
+
+	list, err := cm.AssetsClient.ListAssets(ctx, &assets.ListAssetsConfig{Collection: heroes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Result) != 1 {
+		t.Errorf("got %v assets, want 1", len(list.Result))
+	}
+
+	if got := cm.SpotPriceClient.RetrieveSpotPrice(coinbase.CryptoETH, coinbase.FiatUSD); got != 2000 {
+		t.Errorf("got spot price %v, want 2000", got)
+	}
+
+	if _, err := cm.AssetsClient.GetAsset(ctx, heroes, "2", true); err == nil || err.Error() != "service unavailable" {
+		t.Errorf("got error %v, want the recorded one", err)
+	}
+}
+
+func TestReplayNotRecorded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	record(t, path)
+	cm := replayClients(t, path)
+
+	// Only the timestamps are ignored, any other change is a new request.
+	cfg := pollConfig(start)
+	cfg.Status = "filled"
+	if _, err := cm.OrdersClient.ListOrders(context.Background(), cfg); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("got error %v, want %v", err, ErrNotRecorded)
+	}
+
+	if _, err := cm.AssetsClient.GetAsset(context.Background(), heroes, "1", false); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("got error %v, want %v", err, ErrNotRecorded)
+	}
+
+	if got := cm.SpotPriceClient.RetrieveSpotPrice(coinbase.CryptoETH, coinbase.FiatGBP); got != 0 {
+		t.Errorf("got spot price %v for an unrecorded pair, want 0", got)
+	}
+}
+
+func TestRecordRefusesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	record(t, path)
+
+	if _, err := Open(path, ModeRecord); err == nil {
+		t.Error("recorded over an existing cassette")
+	}
+	if _, err := Open(path, "rewind"); err == nil {
+		t.Error("opened a cassette in an unknown mode")
+	}
+}
+
+// serviceAssets is an assets client that must be started.
+type serviceAssets struct {
+	*fake.Assets
+	started bool
+}
+
+func (s *serviceAssets) Start() error {
+	s.started = true
+	return nil
+}
+
+func (s *serviceAssets) Stop() {
+	s.started = false
+}
+
+func TestWrapStartsClients(t *testing.T) {
+	dir := t.TempDir()
+	record(t, filepath.Join(dir, "replay.jsonl"))
+
+	tests := []struct {
+		name string
+		mode string
+		path string
+		want bool
+	}{
+		{name: "recording", mode: ModeRecord, path: filepath.Join(dir, "record.jsonl"), want: true},
+		{name: "replaying", mode: ModeReplay, path: filepath.Join(dir, "replay.jsonl"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Open(tt.path, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			client := &serviceAssets{Assets: fake.NewAssets()}
+			cm := fake.NewClientsManager(nil, nil, nil)
+			cm.AssetsClient = client
+			Wrap(cm, c)
+
+			if err := cm.Start(); err != nil {
+				t.Fatal(err)
+			}
+			if client.started != tt.want {
+				t.Errorf("started = %v, want %v", client.started, tt.want)
+			}
+
+			cm.Stop()
+			if client.started {
+				t.Error("still started after Stop")
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package cassette
+
+import (
+	"context"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// Wrap routes the clients of cm through the cassette. When replaying, the
+// original clients are never called nor started.
+func Wrap(cm *api.ClientsManager, c *Cassette) {
+	oc := &ordersClient{cassette: c, next: cm.OrdersClient, pager: cm.OrdersPager}
+	cm.OrdersClient = oc
+	cm.OrdersPager = oc
+	cm.AssetsClient = &assetsClient{cassette: c, next: cm.AssetsClient}
+	cm.SpotPriceClient = &spotPriceClient{cassette: c, next: cm.SpotPriceClient}
+	if c.Replaying() {
+		cm.CollectionsClient = noopService{}
+	}
+}
+
+type noopService struct{}
+
+func (noopService) Start() error { return nil }
+func (noopService) Stop()        {}
+
+// startNext starts the wrapped client when it is an api.Service, unless it is
+// replaced by the cassette.
+func startNext(c *Cassette, next interface{}) error {
+	if s, ok := next.(api.Service); ok && !c.Replaying() {
+		return s.Start()
+	}
+
+	return nil
+}
+
+func stopNext(c *Cassette, next interface{}) {
+	if s, ok := next.(api.Service); ok && !c.Replaying() {
+		s.Stop()
+	}
+}
+
+type ordersClient struct {
+	cassette *Cassette
+	next     api.OrdersClient
+	pager    api.OrdersPager
+}
+
+// ordersRequest drops the moving timestamps watchers poll with, which would
+// never match on replay.
+func ordersRequest(cfg *orders.ListOrdersConfig) orders.ListOrdersConfig {
+	req := *cfg
+	req.MinTimestamp = ""
+	req.UpdatedMinTimestamp = ""
+	return req
+}
+
+func (c *ordersClient) Start() error { return startNext(c.cassette, c.next) }
+func (c *ordersClient) Stop()        { stopNext(c.cassette, c.next) }
+
+func (c *ordersClient) ListOrdersPage(ctx context.Context, cfg *orders.ListOrdersConfig) (*imxapi.ListOrdersResponse, error) {
+	req := ordersRequest(cfg)
+
+	if c.cassette.Replaying() {
+		var resp imxapi.ListOrdersResponse
+		if err := c.cassette.replay("orders.page", req, &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+
+	resp, err := c.pager.ListOrdersPage(ctx, cfg)
+	c.cassette.record("orders.page", req, resp, err)
+	return resp, err
+}
+
+func (c *ordersClient) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]imxapi.Order, error) {
+	req := ordersRequest(cfg)
+
+	if c.cassette.Replaying() {
+		var result []imxapi.Order
+		err := c.cassette.replay("orders.list", req, &result)
+		return result, err
+	}
+
+	result, err := c.next.ListOrders(ctx, cfg)
+	c.cassette.record("orders.list", req, result, err)
+	return result, err
+}
+
+type assetsClient struct {
+	cassette *Cassette
+	next     api.AssetsClient
+}
+
+func (c *assetsClient) Start() error { return startNext(c.cassette, c.next) }
+func (c *assetsClient) Stop()        { stopNext(c.cassette, c.next) }
+
+type getAssetRequest struct {
+	TokenAddress string `json:"token_address"`
+	TokenID      string `json:"token_id"`
+	IncludeFees  bool   `json:"include_fees"`
+}
+
+func (c *assetsClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*imxapi.Asset, error) {
+	req := getAssetRequest{TokenAddress: tokenAddress, TokenID: tokenID, IncludeFees: includeFees}
+
+	if c.cassette.Replaying() {
+		var asset imxapi.Asset
+		if err := c.cassette.replay("assets.get", req, &asset); err != nil {
+			return nil, err
+		}
+		return &asset, nil
+	}
+
+	asset, err := c.next.GetAsset(ctx, tokenAddress, tokenID, includeFees)
+	c.cassette.record("assets.get", req, asset, err)
+	return asset, err
+}
+
+func (c *assetsClient) ListAssets(ctx context.Context, cfg *assets.ListAssetsConfig) (*imxapi.ListAssetsResponse, error) {
+	if c.cassette.Replaying() {
+		var resp imxapi.ListAssetsResponse
+		if err := c.cassette.replay("assets.list", cfg, &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+
+	resp, err := c.next.ListAssets(ctx, cfg)
+	c.cassette.record("assets.list", cfg, resp, err)
+	return resp, err
+}
+
+type spotPriceClient struct {
+	cassette *Cassette
+	next     api.SpotPriceClient
+}
+
+func (c *spotPriceClient) Start() error { return startNext(c.cassette, c.next) }
+func (c *spotPriceClient) Stop()        { stopNext(c.cassette, c.next) }
+
+type spotPriceRequest struct {
+	Crypto coinbase.CryptoSymbol `json:"crypto"`
+	Fiat   coinbase.FiatSymbol   `json:"fiat"`
+}
+
+func (c *spotPriceClient) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	req := spotPriceRequest{Crypto: crypto, Fiat: fiat}
+
+	if c.cassette.Replaying() {
+		var price float64
+		if err := c.cassette.replay("spot.price", req, &price); err != nil {
+			log.Errorf("could not replay %v-%v spot price: %v", crypto, fiat, err)
+			return 0
+		}
+		return price
+	}
+
+	price := c.next.RetrieveSpotPrice(crypto, fiat)
+	c.cassette.record("spot.price", req, price, nil)
+	return price
+}
@@ -1,43 +1,75 @@
 package api
 
 import (
+	"github.com/deadloct/bitverse-nft-bot/internal/api/rest"
+	"github.com/deadloct/bitverse-nft-bot/internal/config"
 	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
 	"github.com/deadloct/immutablex-go-lib/collections"
 	"github.com/deadloct/immutablex-go-lib/orders"
 )
 
+// DefaultIMXAPIURL is the public Immutable X API, used for the order pages
+// the immutablex-go-lib client cannot return cursors for.
+const DefaultIMXAPIURL = "https://api.x.immutable.com"
+
 type ClientsManager struct {
-	AssetsClient      assets.Client
-	CollectionsClient collections.Client
-	OrdersClient      orders.Client
+	AssetsClient      AssetsClient
+	CollectionsClient CollectionsClient
+	OrdersClient      OrdersClient
+	OrdersPager       OrdersPager
+	SpotPriceClient   SpotPriceClient
 }
 
+// NewClientsManager uses the immutablex-go-lib clients, unless the IMX_API_URL
+// or COINBASE_API_URL env vars point the bot at another server such as the
+// mock server, in which case the REST clients are used for that API.
 func NewClientsManager() *ClientsManager {
-	return &ClientsManager{
+	cm := &ClientsManager{
 		AssetsClient:      assets.NewClient(assets.NewClientConfig("")),
 		CollectionsClient: collections.NewClient(collections.NewClientConfig("")),
 		OrdersClient:      orders.NewClient(orders.NewClientConfig("")),
+		OrdersPager:       rest.NewOrdersClient(DefaultIMXAPIURL),
+		SpotPriceClient:   coinbase.GetCoinbaseClientInstance(),
 	}
-}
 
-func (cm *ClientsManager) Start() error {
-	if err := cm.AssetsClient.Start(); err != nil {
-		return err
+	if u := config.GetenvStr("IMX_API_URL"); u != "" {
+		ordersClient := rest.NewOrdersClient(u)
+		cm.AssetsClient = rest.NewAssetsClient(u)
+		cm.OrdersClient = ordersClient
+		cm.OrdersPager = ordersClient
 	}
 
-	if err := cm.CollectionsClient.Start(); err != nil {
-		return err
+	if u := config.GetenvStr("COINBASE_API_URL"); u != "" {
+		cm.SpotPriceClient = rest.NewSpotPriceClient(u)
 	}
 
-	if err := cm.OrdersClient.Start(); err != nil {
-		return err
+	return cm
+}
+
+func (cm *ClientsManager) Start() error {
+	for _, s := range cm.services() {
+		if err := s.Start(); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (cm *ClientsManager) Stop() {
-	cm.AssetsClient.Stop()
-	cm.CollectionsClient.Stop()
-	cm.OrdersClient.Stop()
+	for _, s := range cm.services() {
+		s.Stop()
+	}
+}
+
+func (cm *ClientsManager) services() []Service {
+	var services []Service
+	for _, c := range []interface{}{cm.AssetsClient, cm.CollectionsClient, cm.OrdersClient, cm.SpotPriceClient} {
+		if s, ok := c.(Service); ok {
+			services = append(services, s)
+		}
+	}
+
+	return services
 }
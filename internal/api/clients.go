@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// The interfaces below cover the parts of the immutablex-go-lib clients the
+// bot uses, so they can be swapped for the in-memory fakes in package fake.
+
+type OrdersClient interface {
+	ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]imxapi.Order, error)
+}
+
+// OrdersPager lists a single page of orders along with the cursor of the
+// next one, which the immutablex-go-lib client does not return.
+type OrdersPager interface {
+	ListOrdersPage(ctx context.Context, cfg *orders.ListOrdersConfig) (*imxapi.ListOrdersResponse, error)
+}
+
+type AssetsClient interface {
+	GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*imxapi.Asset, error)
+	ListAssets(ctx context.Context, cfg *assets.ListAssetsConfig) (*imxapi.ListAssetsResponse, error)
+}
+
+// CollectionsClient is not queried yet, so it only needs a lifecycle.
+type CollectionsClient interface {
+	Service
+}
+
+type SpotPriceClient interface {
+	RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64
+}
+
+// Service is implemented by clients that must be started before use.
+// ClientsManager starts and stops any of its clients that implement it.
+type Service interface {
+	Start() error
+	Stop()
+}
@@ -0,0 +1,204 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// Assets holds assets by token address and ID. ListAssets pages with the
+// offset of the next asset as the cursor.
+type Assets struct {
+	assets map[string]imxapi.Asset
+	// Err, when set, is returned by every call.
+	Err error
+	mu  sync.Mutex
+}
+
+func NewAssets(list ...imxapi.Asset) *Assets {
+	a := &Assets{assets: make(map[string]imxapi.Asset)}
+	for _, asset := range list {
+		a.Put(asset)
+	}
+
+	return a
+}
+
+func assetKey(tokenAddress, tokenID string) string {
+	return strings.ToLower(tokenAddress) + "/" + tokenID
+}
+
+// Put adds or replaces an asset.
+func (a *Assets) Put(asset imxapi.Asset) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.assets[assetKey(asset.TokenAddress, asset.TokenId)] = asset
+}
+
+func (a *Assets) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*imxapi.Asset, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.Err != nil {
+		return nil, a.Err
+	}
+
+	asset, ok := a.assets[assetKey(tokenAddress, tokenID)]
+	if !ok {
+		return nil, fmt.Errorf("asset %v/%v not found", tokenAddress, tokenID)
+	}
+
+	return &asset, nil
+}
+
+// ListAssets filters by collection, owner, status, metadata and name, sorted
+// by token ID, name or update time. Sell orders are not attached, so asking for
+// them is an error.
+func (a *Assets) ListAssets(ctx context.Context, cfg *assets.ListAssetsConfig) (*imxapi.ListAssetsResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.Err != nil {
+		return nil, a.Err
+	}
+
+	if cfg.SellOrders {
+		return nil, errors.New("sell orders are not supported")
+	}
+
+	var metadata map[string][]string
+	if cfg.Metadata != "" {
+		if err := json.Unmarshal([]byte(cfg.Metadata), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	less, err := assetLess(cfg.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Direction {
+	case "", "asc":
+	case "desc":
+		asc := less
+		less = func(a, b imxapi.Asset) bool { return asc(b, a) }
+	default:
+		return nil, fmt.Errorf("unsupported direction %q", cfg.Direction)
+	}
+
+	var matches []imxapi.Asset
+	for _, asset := range a.assets {
+		if cfg.Collection != "" && !strings.EqualFold(asset.TokenAddress, cfg.Collection) {
+			continue
+		}
+		if cfg.User != "" && !strings.EqualFold(asset.GetUser(), cfg.User) {
+			continue
+		}
+		if cfg.Status != "" && asset.Status != cfg.Status {
+			continue
+		}
+		if cfg.Name != "" && !strings.Contains(strings.ToLower(asset.GetName()), strings.ToLower(cfg.Name)) {
+			continue
+		}
+		if !matchesMetadata(asset, metadata) {
+			continue
+		}
+
+		matches = append(matches, asset)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].TokenAddress != matches[j].TokenAddress {
+			return matches[i].TokenAddress < matches[j].TokenAddress
+		}
+
+		return less(matches[i], matches[j])
+	})
+
+	start := 0
+	if cfg.Cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cfg.Cursor); err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", cfg.Cursor)
+		}
+	}
+	if start > len(matches) {
+		start = len(matches)
+	}
+
+	end := len(matches)
+	if cfg.PageSize > 0 && start+cfg.PageSize < end {
+		end = start + cfg.PageSize
+	}
+
+	resp := &imxapi.ListAssetsResponse{}
+	for _, asset := range matches[start:end] {
+		resp.Result = append(resp.Result, imxapi.AssetWithOrders{
+			TokenId:      asset.TokenId,
+			TokenAddress: asset.TokenAddress,
+			Name:         asset.Name,
+			ImageUrl:     asset.ImageUrl,
+			Metadata:     asset.Metadata,
+			Status:       asset.Status,
+			User:         asset.User,
+		})
+	}
+
+	if end < len(matches) {
+		resp.Cursor = strconv.Itoa(end)
+		resp.Remaining = 1
+	}
+
+	return resp, nil
+}
+
+func matchesMetadata(asset imxapi.Asset, metadata map[string][]string) bool {
+	for key, values := range metadata {
+		if !containsValue(values, asset.GetMetadata()[key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// assetLess orders the assets of a collection by the order_by field of the
+// assets API. Ties and the default go by numeric token ID.
+func assetLess(orderBy string) (func(a, b imxapi.Asset) bool, error) {
+	byTokenID := func(a, b imxapi.Asset) bool {
+		x, _ := strconv.Atoi(a.TokenId)
+		y, _ := strconv.Atoi(b.TokenId)
+		return x < y
+	}
+
+	switch orderBy {
+	case "":
+		return byTokenID, nil
+	case "name":
+		return func(a, b imxapi.Asset) bool {
+			if a.GetName() != b.GetName() {
+				return a.GetName() < b.GetName()
+			}
+			return byTokenID(a, b)
+		}, nil
+	case "updated_at":
+		return func(a, b imxapi.Asset) bool {
+			if a.GetUpdatedAt() != b.GetUpdatedAt() {
+				return a.GetUpdatedAt() < b.GetUpdatedAt()
+			}
+			return byTokenID(a, b)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported order_by %q", orderBy)
+	}
+}
@@ -0,0 +1,164 @@
+package fake
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func newTestAssets() *Assets {
+	other := "0x3333333333333333333333333333333333333333"
+	burned := Asset(heroes, "4", other, nil)
+	burned.Status = "burned"
+
+	return NewAssets(
+		Asset(heroes, "10", owner, map[string]interface{}{"Rarity": "Rare"}),
+		Asset(heroes, "9", owner, map[string]interface{}{"Rarity": "Common"}),
+		Asset(heroes, "2", other, map[string]interface{}{"Rarity": "Rare"}),
+		burned,
+		Asset(portals, "1", owner, nil),
+	)
+}
+
+func assetKeys(result []imxapi.AssetWithOrders) []string {
+	keys := []string{}
+	for _, asset := range result {
+		keys = append(keys, asset.TokenAddress+"/"+asset.TokenId)
+	}
+
+	return keys
+}
+
+func TestAssetsListAssets(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        assets.ListAssetsConfig
+		want       []string
+		wantCursor string
+	}{
+		{
+			name: "sorted by collection then numeric token ID",
+			cfg:  assets.ListAssetsConfig{},
+			want: []string{"0xheroes/2", "0xheroes/4", "0xheroes/9", "0xheroes/10", "0xportals/1"},
+		},
+		{
+			name: "collection ignores case",
+			cfg:  assets.ListAssetsConfig{Collection: "0xPORTALS"},
+			want: []string{"0xportals/1"},
+		},
+		{
+			name: "owner",
+			cfg:  assets.ListAssetsConfig{User: owner},
+			want: []string{"0xheroes/9", "0xheroes/10", "0xportals/1"},
+		},
+		{
+			name: "status",
+			cfg:  assets.ListAssetsConfig{Collection: heroes, Status: "imx"},
+			want: []string{"0xheroes/2", "0xheroes/9", "0xheroes/10"},
+		},
+		{
+			name:       "first page",
+			cfg:        assets.ListAssetsConfig{PageSize: 2},
+			want:       []string{"0xheroes/2", "0xheroes/4"},
+			wantCursor: "2",
+		},
+		{
+			name:       "middle page",
+			cfg:        assets.ListAssetsConfig{PageSize: 2, Cursor: "2"},
+			want:       []string{"0xheroes/9", "0xheroes/10"},
+			wantCursor: "4",
+		},
+		{
+			name: "last page",
+			cfg:  assets.ListAssetsConfig{PageSize: 2, Cursor: "4"},
+			want: []string{"0xportals/1"},
+		},
+		{
+			name: "cursor past the end",
+			cfg:  assets.ListAssetsConfig{Cursor: "10"},
+			want: []string{},
+		},
+		{
+			name: "metadata",
+			cfg:  assets.ListAssetsConfig{Metadata: `{"Rarity":["Rare","Epic"]}`},
+			want: []string{"0xheroes/2", "0xheroes/10"},
+		},
+		{
+			name: "name ignores case",
+			cfg:  assets.ListAssetsConfig{Name: "item #1"},
+			want: []string{"0xheroes/10", "0xportals/1"},
+		},
+		{
+			name: "token IDs descending",
+			cfg:  assets.ListAssetsConfig{Collection: heroes, Direction: "desc"},
+			want: []string{"0xheroes/10", "0xheroes/9", "0xheroes/4", "0xheroes/2"},
+		},
+		{
+			name: "by name",
+			cfg:  assets.ListAssetsConfig{Collection: heroes, OrderBy: "name"},
+			want: []string{"0xheroes/10", "0xheroes/2", "0xheroes/4", "0xheroes/9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			resp, err := newTestAssets().ListAssets(context.Background(), &cfg)
+			if err != nil {
+				t.Fatalf("ListAssets() error = %v", err)
+			}
+
+			if got := assetKeys(resp.Result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAssets() = %v, want %v", got, tt.want)
+			}
+
+			if resp.Cursor != tt.wantCursor {
+				t.Errorf("ListAssets() cursor = %q, want %q", resp.Cursor, tt.wantCursor)
+			}
+
+			if wantRemaining := tt.wantCursor != ""; (resp.Remaining > 0) != wantRemaining {
+				t.Errorf("ListAssets() remaining = %v, want more pages %v", resp.Remaining, wantRemaining)
+			}
+		})
+	}
+}
+
+func TestAssetsListAssetsUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  assets.ListAssetsConfig
+	}{
+		{name: "sell orders", cfg: assets.ListAssetsConfig{SellOrders: true}},
+		{name: "order by", cfg: assets.ListAssetsConfig{OrderBy: "price"}},
+		{name: "direction", cfg: assets.ListAssetsConfig{Direction: "up"}},
+		{name: "metadata", cfg: assets.ListAssetsConfig{Metadata: "Rarity=Rare"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if _, err := newTestAssets().ListAssets(context.Background(), &cfg); err == nil {
+				t.Error("ListAssets() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestAssetsGetAsset(t *testing.T) {
+	a := newTestAssets()
+
+	asset, err := a.GetAsset(context.Background(), "0xHeroes", "9", false)
+	if err != nil {
+		t.Fatalf("GetAsset() error = %v", err)
+	}
+	if asset.TokenId != "9" || asset.GetUser() != owner {
+		t.Errorf("GetAsset() = %v/%v owned by %v, want 9 owned by %v", asset.TokenAddress, asset.TokenId, asset.GetUser(), owner)
+	}
+
+	if _, err := a.GetAsset(context.Background(), heroes, "404", false); err == nil {
+		t.Error("GetAsset() of a missing asset error = nil, want an error")
+	}
+}
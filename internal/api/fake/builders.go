@@ -0,0 +1,57 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// Order builds an active ETH sell order for the token, priced with fees and
+// created and updated at the given time.
+func Order(id int32, tokenAddress, tokenID string, eth float64, at time.Time) imxapi.Order {
+	decimals := int32(18)
+	name := fmt.Sprintf("Item #%s", tokenID)
+	timestamp := at.UTC().Format(time.RFC3339)
+	wei := fmt.Sprintf("%.0f", eth*1e18)
+
+	return imxapi.Order{
+		OrderId: id,
+		Status:  "active",
+		User:    "0x1111111111111111111111111111111111111111",
+		Sell: imxapi.Token{
+			Type: "ERC721",
+			Data: imxapi.TokenData{
+				TokenAddress: &tokenAddress,
+				TokenId:      &tokenID,
+				Quantity:     "1",
+				Properties:   &imxapi.AssetProperties{Name: *imxapi.NewNullableString(&name)},
+			},
+		},
+		Buy: imxapi.Token{
+			Type: "ETH",
+			Data: imxapi.TokenData{
+				Decimals:         &decimals,
+				Quantity:         wei,
+				QuantityWithFees: wei,
+			},
+		},
+		Timestamp:        *imxapi.NewNullableString(&timestamp),
+		UpdatedTimestamp: *imxapi.NewNullableString(&timestamp),
+	}
+}
+
+// Asset builds an asset owned by user with the given metadata.
+func Asset(tokenAddress, tokenID, user string, metadata map[string]interface{}) imxapi.Asset {
+	name := fmt.Sprintf("Item #%s", tokenID)
+	imageURL := fmt.Sprintf("https://example.com/%s.png", tokenID)
+	return imxapi.Asset{
+		TokenAddress: tokenAddress,
+		TokenId:      tokenID,
+		Name:         *imxapi.NewNullableString(&name),
+		ImageUrl:     *imxapi.NewNullableString(&imageURL),
+		Metadata:     metadata,
+		Status:       "imx",
+		User:         user,
+	}
+}
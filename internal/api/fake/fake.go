@@ -0,0 +1,44 @@
+// Package fake provides in-memory implementations of the api client
+// interfaces so handlers, watchers and commands can run without Immutable X
+// or Coinbase.
+package fake
+
+import (
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+)
+
+// NewClientsManager returns a ClientsManager backed by the fakes. Any nil
+// argument is replaced with an empty fake.
+func NewClientsManager(orders *Orders, assets *Assets, spot *SpotPrices) *api.ClientsManager {
+	if assets == nil {
+		assets = NewAssets()
+	}
+	if orders == nil {
+		orders = NewOrders(assets)
+	}
+	if spot == nil {
+		spot = NewSpotPrices()
+	}
+
+	return &api.ClientsManager{
+		AssetsClient:      assets,
+		CollectionsClient: &Collections{},
+		OrdersClient:      orders,
+		OrdersPager:       orders,
+		SpotPriceClient:   spot,
+	}
+}
+
+// Collections records whether it was started, there is nothing to query.
+type Collections struct {
+	Started bool
+}
+
+func (c *Collections) Start() error {
+	c.Started = true
+	return nil
+}
+
+func (c *Collections) Stop() {
+	c.Started = false
+}
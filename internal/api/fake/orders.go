@@ -0,0 +1,205 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// Orders holds orders and answers ListOrders like Immutable X for the filters
+// the bot uses. SellMetadata filters are checked against the metadata of the
+// matching asset in Assets. Pages use the offset of the next order as the
+// cursor.
+type Orders struct {
+	assets *Assets
+	orders []imxapi.Order
+	// Err, when set, is returned by every call.
+	Err error
+	// Calls counts the requests made, failed ones included.
+	Calls int
+	mu    sync.Mutex
+}
+
+func NewOrders(assets *Assets, list ...imxapi.Order) *Orders {
+	return &Orders{assets: assets, orders: list}
+}
+
+// Put adds an order, replacing any existing order with the same ID.
+func (o *Orders) Put(order imxapi.Order) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, existing := range o.orders {
+		if existing.OrderId == order.OrderId {
+			o.orders[i] = order
+			return
+		}
+	}
+
+	o.orders = append(o.orders, order)
+}
+
+func (o *Orders) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]imxapi.Order, error) {
+	resp, err := o.ListOrdersPage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result, nil
+}
+
+func (o *Orders) ListOrdersPage(ctx context.Context, cfg *orders.ListOrdersConfig) (*imxapi.ListOrdersResponse, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.Calls++
+	if o.Err != nil {
+		return nil, o.Err
+	}
+
+	var metadata map[string][]string
+	if cfg.SellMetadata != "" {
+		if err := json.Unmarshal([]byte(cfg.SellMetadata), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid sell metadata: %w", err)
+		}
+	}
+
+	var result []imxapi.Order
+	for _, order := range o.orders {
+		if o.matches(order, cfg, metadata) {
+			result = append(result, order)
+		}
+	}
+
+	less := orderLess(cfg.OrderBy)
+	sort.SliceStable(result, func(i, j int) bool {
+		if cfg.Direction == "desc" {
+			return less(result[j], result[i])
+		}
+
+		return less(result[i], result[j])
+	})
+
+	start := 0
+	if cfg.Cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cfg.Cursor); err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid cursor %q", cfg.Cursor)
+		}
+	}
+	if start > len(result) {
+		start = len(result)
+	}
+
+	end := len(result)
+	if cfg.PageSize > 0 && start+cfg.PageSize < end {
+		end = start + cfg.PageSize
+	}
+
+	resp := &imxapi.ListOrdersResponse{Result: result[start:end]}
+	if end < len(result) {
+		resp.Cursor = strconv.Itoa(end)
+		resp.Remaining = 1
+	}
+
+	return resp, nil
+}
+
+func (o *Orders) matches(order imxapi.Order, cfg *orders.ListOrdersConfig, metadata map[string][]string) bool {
+	sell := order.Sell.GetData()
+	tokenAddress, tokenID := sell.GetTokenAddress(), sell.GetTokenId()
+
+	switch {
+	case cfg.SellTokenAddress != "" && !strings.EqualFold(tokenAddress, cfg.SellTokenAddress):
+		return false
+	case cfg.SellTokenID != "" && tokenID != cfg.SellTokenID:
+		return false
+	case cfg.Status != "" && order.Status != cfg.Status:
+		return false
+	case cfg.User != "" && !strings.EqualFold(order.GetUser(), cfg.User):
+		return false
+	case cfg.BuyTokenType != "" && order.GetBuy().Type != cfg.BuyTokenType:
+		return false
+	case !notBefore(order.GetTimestamp(), cfg.MinTimestamp):
+		return false
+	case !notBefore(order.GetUpdatedTimestamp(), cfg.UpdatedMinTimestamp):
+		return false
+	}
+
+	if len(metadata) == 0 {
+		return true
+	}
+
+	if o.assets == nil {
+		return false
+	}
+
+	asset, err := o.assets.GetAsset(context.Background(), tokenAddress, tokenID, false)
+	if err != nil {
+		return false
+	}
+
+	for key, values := range metadata {
+		if !containsValue(values, asset.GetMetadata()[key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// notBefore reports whether the timestamp is at or after min, treating an
+// empty min as no limit.
+func notBefore(timestamp, min string) bool {
+	if min == "" {
+		return true
+	}
+
+	m, err := time.Parse(time.RFC3339, min)
+	if err != nil {
+		return true
+	}
+
+	t, err := time.Parse(time.RFC3339, timestamp)
+	return err == nil && !t.Before(m)
+}
+
+func containsValue(values []string, v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	for _, value := range values {
+		if value == fmt.Sprint(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func orderLess(orderBy string) func(a, b imxapi.Order) bool {
+	switch orderBy {
+	case "created_at":
+		return func(a, b imxapi.Order) bool { return a.GetTimestamp() < b.GetTimestamp() }
+	case "updated_at":
+		return func(a, b imxapi.Order) bool { return a.GetUpdatedTimestamp() < b.GetUpdatedTimestamp() }
+	case "buy_quantity_with_fees":
+		return func(a, b imxapi.Order) bool { return quantity(a) < quantity(b) }
+	default:
+		return func(a, b imxapi.Order) bool { return a.OrderId < b.OrderId }
+	}
+}
+
+func quantity(order imxapi.Order) float64 {
+	q, _ := strconv.ParseFloat(order.GetBuy().Data.QuantityWithFees, 64)
+	return q
+}
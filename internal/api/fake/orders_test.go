@@ -0,0 +1,221 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	heroes  = "0xheroes"
+	portals = "0xportals"
+	owner   = "0x2222222222222222222222222222222222222222"
+)
+
+var start = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestOrders() *Orders {
+	assets := NewAssets(
+		Asset(heroes, "1", owner, map[string]interface{}{"Rarity": "Common"}),
+		Asset(heroes, "2", owner, map[string]interface{}{"Rarity": "Rare"}),
+		Asset(heroes, "3", owner, map[string]interface{}{"Rarity": "Common"}),
+		Asset(portals, "1", owner, map[string]interface{}{"Rarity": "Epic"}),
+	)
+
+	sold := Order(4, heroes, "3", 0.01, start.Add(3*time.Hour))
+	sold.Status = "filled"
+
+	usdc := Order(5, portals, "1", 0.04, start.Add(4*time.Hour))
+	usdc.Buy.Type = "ERC20"
+
+	return NewOrders(assets,
+		Order(1, heroes, "1", 0.05, start),
+		Order(2, heroes, "2", 0.02, start.Add(time.Hour)),
+		Order(3, heroes, "3", 0.03, start.Add(2*time.Hour)),
+		sold,
+		usdc,
+	)
+}
+
+func orderIDs(result []imxapi.Order) []int32 {
+	ids := []int32{}
+	for _, order := range result {
+		ids = append(ids, order.OrderId)
+	}
+
+	return ids
+}
+
+func TestOrdersListOrders(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  orders.ListOrdersConfig
+		want []int32
+	}{
+		{
+			name: "no filters sorts by order ID",
+			cfg:  orders.ListOrdersConfig{},
+			want: []int32{1, 2, 3, 4, 5},
+		},
+		{
+			name: "status",
+			cfg:  orders.ListOrdersConfig{Status: "active"},
+			want: []int32{1, 2, 3, 5},
+		},
+		{
+			name: "token address ignores case",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: "0xHEROES", Status: "active"},
+			want: []int32{1, 2, 3},
+		},
+		{
+			name: "token ID",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: heroes, SellTokenID: "3"},
+			want: []int32{3, 4},
+		},
+		{
+			name: "buy token type",
+			cfg:  orders.ListOrdersConfig{BuyTokenType: "ERC20"},
+			want: []int32{5},
+		},
+		{
+			name: "metadata is checked against assets",
+			cfg:  orders.ListOrdersConfig{SellMetadata: `{"Rarity":["Common"]}`, Status: "active"},
+			want: []int32{1, 3},
+		},
+		{
+			name: "metadata with several values",
+			cfg:  orders.ListOrdersConfig{SellMetadata: `{"Rarity":["Rare","Epic"]}`},
+			want: []int32{2, 5},
+		},
+		{
+			name: "cheapest first",
+			cfg:  orders.ListOrdersConfig{Status: "active", OrderBy: "buy_quantity_with_fees", Direction: "asc"},
+			want: []int32{2, 3, 5, 1},
+		},
+		{
+			name: "most expensive first",
+			cfg:  orders.ListOrdersConfig{Status: "active", OrderBy: "buy_quantity_with_fees", Direction: "desc"},
+			want: []int32{1, 5, 3, 2},
+		},
+		{
+			name: "newest first",
+			cfg:  orders.ListOrdersConfig{OrderBy: "created_at", Direction: "desc"},
+			want: []int32{5, 4, 3, 2, 1},
+		},
+		{
+			name: "min timestamp is inclusive",
+			cfg:  orders.ListOrdersConfig{OrderBy: "created_at", MinTimestamp: start.Add(2 * time.Hour).Format(time.RFC3339)},
+			want: []int32{3, 4, 5},
+		},
+		{
+			name: "updated min timestamp",
+			cfg:  orders.ListOrdersConfig{Status: "filled", UpdatedMinTimestamp: start.Add(time.Hour).Format(time.RFC3339)},
+			want: []int32{4},
+		},
+		{
+			name: "page size",
+			cfg:  orders.ListOrdersConfig{OrderBy: "created_at", PageSize: 2},
+			want: []int32{1, 2},
+		},
+		{
+			name: "cursor",
+			cfg:  orders.ListOrdersConfig{OrderBy: "created_at", PageSize: 2, Cursor: "2"},
+			want: []int32{3, 4},
+		},
+		{
+			name: "no matches",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: "0xother"},
+			want: []int32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			result, err := newTestOrders().ListOrders(context.Background(), &cfg)
+			if err != nil {
+				t.Fatalf("ListOrders() error = %v", err)
+			}
+
+			if got := orderIDs(result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrdersListOrdersPage(t *testing.T) {
+	o := newTestOrders()
+	cfg := &orders.ListOrdersConfig{OrderBy: "created_at", PageSize: 2}
+
+	var pages [][]int32
+	for {
+		resp, err := o.ListOrdersPage(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("ListOrdersPage(cursor %q) error = %v", cfg.Cursor, err)
+		}
+
+		pages = append(pages, orderIDs(resp.Result))
+		if resp.Remaining == 0 {
+			if resp.Cursor != "" {
+				t.Errorf("last page cursor = %q, want empty", resp.Cursor)
+			}
+			break
+		}
+
+		cfg.Cursor = resp.Cursor
+	}
+
+	want := [][]int32{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestOrdersErrors(t *testing.T) {
+	failing := newTestOrders()
+	failing.Err = errors.New("unavailable")
+
+	tests := []struct {
+		name   string
+		orders *Orders
+		cfg    orders.ListOrdersConfig
+	}{
+		{name: "injected error", orders: failing},
+		{name: "invalid metadata", orders: newTestOrders(), cfg: orders.ListOrdersConfig{SellMetadata: "{"}},
+		{name: "invalid cursor", orders: newTestOrders(), cfg: orders.ListOrdersConfig{Cursor: "next"}},
+		{name: "negative cursor", orders: newTestOrders(), cfg: orders.ListOrdersConfig{Cursor: "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if _, err := tt.orders.ListOrders(context.Background(), &cfg); err == nil {
+				t.Error("ListOrders() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestOrdersPut(t *testing.T) {
+	o := newTestOrders()
+
+	cheaper := Order(1, heroes, "1", 0.001, start)
+	o.Put(cheaper)
+	o.Put(Order(6, heroes, "2", 0.5, start.Add(5*time.Hour)))
+
+	cfg := &orders.ListOrdersConfig{SellTokenAddress: heroes, Status: "active", OrderBy: "buy_quantity_with_fees"}
+	result, err := o.ListOrders(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+
+	if got, want := orderIDs(result), []int32{1, 2, 3, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListOrders() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package fake
+
+import (
+	"sync"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// SpotPrices returns fixed exchange rates. Unset pairs are worth 0, like a
+// failed Coinbase lookup.
+type SpotPrices struct {
+	prices map[coinbase.CryptoSymbol]map[coinbase.FiatSymbol]float64
+	mu     sync.Mutex
+}
+
+func NewSpotPrices() *SpotPrices {
+	return &SpotPrices{prices: make(map[coinbase.CryptoSymbol]map[coinbase.FiatSymbol]float64)}
+}
+
+func (s *SpotPrices) Set(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.prices[crypto] == nil {
+		s.prices[crypto] = make(map[coinbase.FiatSymbol]float64)
+	}
+	s.prices[crypto][fiat] = price
+}
+
+func (s *SpotPrices) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.prices[crypto][fiat]
+}
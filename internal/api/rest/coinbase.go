@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+// SpotPriceClient reads spot prices from the Coinbase v2 API. Like the
+// coinbase package client, failed lookups are logged and return 0.
+type SpotPriceClient struct {
+	client
+}
+
+func NewSpotPriceClient(baseURL string) *SpotPriceClient {
+	return &SpotPriceClient{client: newClient(baseURL)}
+}
+
+type spotPriceResponse struct {
+	Data struct {
+		Amount   string `json:"amount"`
+		Base     string `json:"base"`
+		Currency string `json:"currency"`
+	} `json:"data"`
+}
+
+func (c *SpotPriceClient) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	var resp spotPriceResponse
+	if err := c.get(ctx, fmt.Sprintf("/v2/prices/%s-%s/spot", crypto, fiat), nil, &resp); err != nil {
+		log.Errorf("could not retrieve %v-%v spot price: %v", crypto, fiat, err)
+		return 0
+	}
+
+	amount, err := strconv.ParseFloat(resp.Data.Amount, 64)
+	if err != nil {
+		log.Errorf("invalid %v-%v spot price %q: %v", crypto, fiat, resp.Data.Amount, err)
+		return 0
+	}
+
+	return amount
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// OrdersClient lists orders from the Immutable X v1 API.
+type OrdersClient struct {
+	client
+}
+
+func NewOrdersClient(baseURL string) *OrdersClient {
+	return &OrdersClient{client: newClient(baseURL)}
+}
+
+func (c *OrdersClient) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]imxapi.Order, error) {
+	resp, err := c.ListOrdersPage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result, nil
+}
+
+func (c *OrdersClient) ListOrdersPage(ctx context.Context, cfg *orders.ListOrdersConfig) (*imxapi.ListOrdersResponse, error) {
+	query := url.Values{}
+	set(query, "buy_token_type", cfg.BuyTokenType)
+	set(query, "cursor", cfg.Cursor)
+	set(query, "direction", cfg.Direction)
+	set(query, "order_by", cfg.OrderBy)
+	set(query, "sell_metadata", cfg.SellMetadata)
+	set(query, "sell_token_address", cfg.SellTokenAddress)
+	set(query, "sell_token_id", cfg.SellTokenID)
+	set(query, "status", cfg.Status)
+	set(query, "min_timestamp", cfg.MinTimestamp)
+	set(query, "updated_min_timestamp", cfg.UpdatedMinTimestamp)
+	set(query, "user", cfg.User)
+	if cfg.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(cfg.PageSize))
+	}
+
+	var resp imxapi.ListOrdersResponse
+	if err := c.get(ctx, "/v1/orders", query, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// AssetsClient reads assets from the Immutable X v1 API.
+type AssetsClient struct {
+	client
+}
+
+func NewAssetsClient(baseURL string) *AssetsClient {
+	return &AssetsClient{client: newClient(baseURL)}
+}
+
+func (c *AssetsClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*imxapi.Asset, error) {
+	query := url.Values{}
+	if includeFees {
+		query.Set("include_fees", "true")
+	}
+
+	var asset imxapi.Asset
+	path := fmt.Sprintf("/v1/assets/%s/%s", url.PathEscape(tokenAddress), url.PathEscape(tokenID))
+	if err := c.get(ctx, path, query, &asset); err != nil {
+		return nil, err
+	}
+
+	return &asset, nil
+}
+
+func (c *AssetsClient) ListAssets(ctx context.Context, cfg *assets.ListAssetsConfig) (*imxapi.ListAssetsResponse, error) {
+	query := url.Values{}
+	set(query, "collection", cfg.Collection)
+	set(query, "cursor", cfg.Cursor)
+	set(query, "direction", cfg.Direction)
+	set(query, "metadata", cfg.Metadata)
+	set(query, "name", cfg.Name)
+	set(query, "order_by", cfg.OrderBy)
+	set(query, "status", cfg.Status)
+	set(query, "user", cfg.User)
+	if cfg.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(cfg.PageSize))
+	}
+	if cfg.SellOrders {
+		query.Set("sell_orders", "true")
+	}
+
+	var resp imxapi.ListAssetsResponse
+	if err := c.get(ctx, "/v1/assets", query, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,62 @@
+// Package rest implements the api client interfaces directly against the
+// Immutable X and Coinbase REST APIs at configurable base URLs, so the bot can
+// be pointed at the mock server in package mockserver.
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func newClient(baseURL string) client {
+	return client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// get decodes the JSON response of GET baseURL+path?query into v.
+func (c client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %v: %v: %s", u, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// set adds the query parameter unless the value is empty.
+func set(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
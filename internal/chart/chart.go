@@ -0,0 +1,226 @@
+// Package chart renders simple line charts to PNG using only the standard
+// library.
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"time"
+)
+
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 400
+
+	textScale    = 2
+	marginLeft   = 90
+	marginRight  = 20
+	marginTop    = 40
+	marginBottom = 50
+	yTicks       = 5
+	xTicks       = 4
+)
+
+var (
+	ErrNoData = errors.New("no data to chart")
+
+	background = color.RGBA{0x2f, 0x31, 0x36, 0xff}
+	foreground = color.RGBA{0xdc, 0xdd, 0xde, 0xff}
+	grid       = color.RGBA{0x4f, 0x54, 0x5c, 0xff}
+)
+
+type Point struct {
+	At    time.Time
+	Value float64
+}
+
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Points []Point
+}
+
+type Chart struct {
+	Title  string
+	Unit   string
+	Width  int
+	Height int
+	Series []Series
+}
+
+// Render draws the chart as a PNG. Points in each series must be sorted by
+// time.
+func Render(w io.Writer, c Chart) error {
+	if c.Width == 0 {
+		c.Width = DefaultWidth
+	}
+	if c.Height == 0 {
+		c.Height = DefaultHeight
+	}
+
+	start, end, lo, hi, ok := bounds(c.Series)
+	if !ok {
+		return ErrNoData
+	}
+
+	// Give flat lines and single points some room.
+	if hi == lo {
+		pad := math.Max(math.Abs(hi)*0.05, 0.0001)
+		lo, hi = lo-pad, hi+pad
+	}
+	if !end.After(start) {
+		start, end = start.Add(-time.Hour), end.Add(time.Hour)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
+	fillRect(img, 0, 0, c.Width, c.Height, background)
+
+	left, top := marginLeft, marginTop
+	right, bottom := c.Width-marginRight, c.Height-marginBottom
+	x := func(t time.Time) int {
+		return left + int(float64(right-left)*float64(t.Sub(start))/float64(end.Sub(start)))
+	}
+	y := func(v float64) int {
+		return bottom - int(float64(bottom-top)*(v-lo)/(hi-lo))
+	}
+
+	for i := 0; i <= yTicks; i++ {
+		v := lo + (hi-lo)*float64(i)/yTicks
+		py := y(v)
+		drawLine(img, left, py, right, py, grid)
+		label := formatValue(v)
+		drawText(img, left-8-textWidth(label, textScale), py-glyphHeight*textScale/2, label, textScale, foreground)
+	}
+
+	for i := 0; i <= xTicks; i++ {
+		t := start.Add(time.Duration(float64(end.Sub(start)) * float64(i) / xTicks))
+		px := x(t)
+		drawLine(img, px, bottom, px, bottom+4, foreground)
+		label := t.UTC().Format("01/02 15:04")
+		lx := px - textWidth(label, textScale)/2
+		lx = min(max(lx, 0), c.Width-textWidth(label, textScale)-1)
+		drawText(img, lx, bottom+10, label, textScale, foreground)
+	}
+
+	drawLine(img, left, top, left, bottom, foreground)
+	drawLine(img, left, bottom, right, bottom, foreground)
+
+	title := c.Title
+	if c.Unit != "" {
+		title = fmt.Sprintf("%s (%s)", title, c.Unit)
+	}
+	drawText(img, left, 12, title, textScale, foreground)
+
+	legendX := right
+	for i := len(c.Series) - 1; i >= 0; i-- {
+		s := c.Series[i]
+		legendX -= textWidth(s.Name, textScale) + 24
+		fillRect(img, legendX, 12, 10, 10, s.Color)
+		drawText(img, legendX+14, 12, s.Name, textScale, foreground)
+	}
+
+	for _, s := range c.Series {
+		for i := 1; i < len(s.Points); i++ {
+			a, b := s.Points[i-1], s.Points[i]
+			drawThickLine(img, x(a.At), y(a.Value), x(b.At), y(b.Value), s.Color)
+		}
+
+		if len(s.Points) == 1 {
+			p := s.Points[0]
+			fillRect(img, x(p.At)-2, y(p.Value)-2, 5, 5, s.Color)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func bounds(series []Series) (start, end time.Time, lo, hi float64, ok bool) {
+	for _, s := range series {
+		for _, p := range s.Points {
+			if !ok {
+				start, end, lo, hi, ok = p.At, p.At, p.Value, p.Value, true
+				continue
+			}
+
+			if p.At.Before(start) {
+				start = p.At
+			}
+			if p.At.After(end) {
+				end = p.At
+			}
+			lo = math.Min(lo, p.Value)
+			hi = math.Max(hi, p.Value)
+		}
+	}
+
+	return start, end, lo, hi, ok
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.Abs(v) >= 100:
+		return fmt.Sprintf("%.0f", v)
+	case math.Abs(v) >= 1:
+		return fmt.Sprintf("%.2f", v)
+	default:
+		return fmt.Sprintf("%.4f", v)
+	}
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			img.Set(px, py, c)
+		}
+	}
+}
+
+func drawThickLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	drawLine(img, x0, y0, x1, y1, c)
+	drawLine(img, x0+1, y0, x1+1, y1, c)
+	drawLine(img, x0, y0+1, x1, y1+1, c)
+}
+
+// drawLine draws a one pixel line with Bresenham's algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	e := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
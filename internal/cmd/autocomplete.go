@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+)
+
+const (
+	MaxAutocompleteChoices   = 25
+	MaxAutocompleteNameChars = 100
+	// MaxHeroCandidates is how many heroes are listed for an ambiguous name.
+	MaxHeroCandidates = 10
+)
+
+func (s *SlashCommands) autocompleteHandler(sess discord.Transport, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, option := range data.Options {
+		if !option.Focused {
+			continue
+		}
+
+		if isHeroOption(data.Name, option.Name) {
+			choices = s.heroChoices(option.StringValue())
+		}
+	}
+
+	err := sess.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		logger.Error(sess, i.Interaction, err)
+	}
+}
+
+func isHeroOption(command, option string) bool {
+	return (command == CMDHero && option == CMDHeroID) || (command == CMDMarket && option == CMDMarketHero)
+}
+
+func (s *SlashCommands) heroChoices(query string) []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	if s.heroIndex == nil {
+		return choices
+	}
+
+	for _, e := range s.heroIndex.Search(query, MaxAutocompleteChoices) {
+		name := fmt.Sprintf("%s (#%s, %s, Level %s)", e.HeroName(), e.TokenID, e.Rarity(), e.Level())
+		if len(name) > MaxAutocompleteNameChars {
+			name = name[:MaxAutocompleteNameChars]
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: e.TokenID})
+	}
+
+	return choices
+}
+
+// resolveHero returns the token ID for a hero option, which holds a token ID
+// when an autocomplete choice was picked or the typed name otherwise. Typed
+// names must match a single hero exactly, ignoring case. Otherwise the reply
+// explaining why, listing the candidates for ambiguous names, is returned.
+func (s *SlashCommands) resolveHero(value string) (string, *discordgo.InteractionResponseData) {
+	if _, err := strconv.Atoi(value); err == nil {
+		return value, nil
+	}
+
+	if s.heroIndex == nil {
+		return "", &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Hero names cannot be looked up, use a token ID instead of %s", value),
+		}
+	}
+
+	candidates := s.heroIndex.Search(value, math.MaxInt)
+	var exact []index.Entry
+	for _, e := range candidates {
+		if strings.EqualFold(e.HeroName(), strings.TrimSpace(value)) {
+			exact = append(exact, e)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		return exact[0].TokenID, nil
+	case len(exact) > 1:
+		candidates = exact
+	case len(candidates) == 0:
+		return "", &discordgo.InteractionResponseData{Content: fmt.Sprintf("Could not find a hero named %s", value)}
+	}
+
+	var names []string
+	for n, e := range candidates {
+		if n == MaxHeroCandidates {
+			names = append(names, fmt.Sprintf("…and %v more", len(candidates)-n))
+			break
+		}
+
+		names = append(names, fmt.Sprintf("%s (#%s)", e.HeroName(), e.TokenID))
+	}
+
+	return "", &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("%s matches more than one hero, pick one from the list or use a token ID: %s", value, strings.Join(names, ", ")),
+	}
+}
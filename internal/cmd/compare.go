@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const (
+	CMDCompare         = "compare"
+	CMDCompareHeroes   = "heroes"
+	CMDCompareCurrency = "output-currency"
+)
+
+func compareCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        CMDCompare,
+		Description: "Compare heroes side by side",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDCompareHeroes,
+				Description: fmt.Sprintf("Hero IDs or names separated by commas (2 to %v)", handlers.MaxCompareAssets),
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDCompareCurrency,
+				Description: "Output currency of listing prices (Default: USD)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "USD", Value: coinbase.FiatUSD},
+					{Name: "EUR", Value: coinbase.FiatEUR},
+					{Name: "GBP", Value: coinbase.FiatGBP},
+				},
+			},
+		},
+	}
+}
+
+func (s *SlashCommands) handleCompare(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	var heroes string
+	currency := coinbase.FiatUSD
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case CMDCompareHeroes:
+			heroes = option.StringValue()
+		case CMDCompareCurrency:
+			currency = coinbase.FiatSymbol(option.StringValue())
+		}
+	}
+
+	var ids []string
+	for _, hero := range strings.Split(heroes, ",") {
+		hero = strings.TrimSpace(hero)
+		if hero == "" {
+			continue
+		}
+
+		id, failed := s.resolveHero(strings.TrimPrefix(hero, "#"))
+		if failed != nil {
+			return failed
+		}
+		ids = append(ids, id)
+	}
+
+	logger.Debugf(sess, i.Interaction, "Compare heroes %v", ids)
+	return s.compareHandler.HandleCommand(ids, currency)
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+const (
+	MarketQueryTTL     = time.Hour
+	MarketButtonPrefix = "market"
+)
+
+// marketQuery is the original /market query, kept so the page buttons can
+// fetch other pages of the same results. pages holds where every page shown
+// so far starts, so each button fetches a single page by its cursor.
+type marketQuery struct {
+	cfg      orders.ListOrdersConfig
+	format   string
+	currency coinbase.FiatSymbol
+	created  time.Time
+	pages    []handlers.PageCursor
+}
+
+type marketQueries struct {
+	queries map[string]*marketQuery
+	mu      sync.Mutex
+}
+
+func newMarketQueries() *marketQueries {
+	return &marketQueries{queries: make(map[string]*marketQuery)}
+}
+
+func (m *marketQueries) add(id string, q *marketQuery) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, v := range m.queries {
+		if time.Since(v.created) > MarketQueryTTL {
+			delete(m.queries, k)
+		}
+	}
+
+	m.queries[id] = q
+}
+
+// page returns the query and where the page starts. ok is false when the
+// query expired, known is false when the page was never reached.
+func (m *marketQueries) page(id string, page int) (q *marketQuery, start handlers.PageCursor, ok, known bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok = m.queries[id]
+	if !ok || time.Since(q.created) > MarketQueryTTL {
+		return nil, handlers.PageCursor{}, false, false
+	}
+
+	if page >= len(q.pages) {
+		return q, handlers.PageCursor{}, true, false
+	}
+
+	return q, q.pages[page], true, true
+}
+
+// setNext records where the page after page starts.
+func (m *marketQueries) setNext(id string, page int, next handlers.PageCursor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queries[id]
+	if !ok || page >= len(q.pages) {
+		return
+	}
+
+	q.pages = append(q.pages[:page+1], next)
+}
+
+// handleMarket runs a new /market query and remembers it for paging.
+func (s *SlashCommands) handleMarket(
+	i *discordgo.InteractionCreate,
+	cfg *orders.ListOrdersConfig,
+	format string,
+	currency coinbase.FiatSymbol,
+) *discordgo.InteractionResponseData {
+	q := &marketQuery{
+		cfg:      *cfg,
+		format:   format,
+		currency: currency,
+		created:  time.Now(),
+		pages:    []handlers.PageCursor{{}},
+	}
+	s.marketQueries.add(i.ID, q)
+	return s.marketPage(i.ID, q, 0, handlers.PageCursor{})
+}
+
+// handleMarketButton shows the page requested by a Previous or Next button.
+// Custom IDs look like market:<query id>:<page>.
+func (s *SlashCommands) handleMarketButton(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		logger.Warnf(sess, i.Interaction, "Invalid market button %v", i.MessageComponentData().CustomID)
+		return &discordgo.InteractionResponseData{Content: "Unknown button"}
+	}
+
+	page, err := strconv.Atoi(parts[2])
+	if err != nil || page < 0 {
+		logger.Warnf(sess, i.Interaction, "Invalid market page %v", parts[2])
+		return &discordgo.InteractionResponseData{Content: "Unknown page"}
+	}
+
+	q, start, ok, known := s.marketQueries.page(parts[1], page)
+	if !ok {
+		empty := []discordgo.MessageComponent{}
+		return &discordgo.InteractionResponseData{
+			Content:    "This market query has expired, run /market again",
+			Components: empty,
+		}
+	}
+
+	if !known {
+		logger.Warnf(sess, i.Interaction, "Market page %v was never reached", page)
+		return &discordgo.InteractionResponseData{Content: "Unknown page"}
+	}
+
+	logger.Debugf(sess, i.Interaction, "Get page %v of orders for cfg %#v at %#v", page, q.cfg, start)
+	return s.marketPage(parts[1], q, page, start)
+}
+
+func (s *SlashCommands) marketPage(id string, q *marketQuery, page int, start handlers.PageCursor) *discordgo.InteractionResponseData {
+	cfg := q.cfg
+	response, next := s.ordersHandler.HandlePage(&cfg, q.format, q.currency, start)
+	if next != nil {
+		s.marketQueries.setNext(id, page, *next)
+	}
+
+	if page == 0 && next == nil {
+		return response
+	}
+
+	response.Components = []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s:%d", MarketButtonPrefix, id, page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s:%d", MarketButtonPrefix, id, page+1),
+					Disabled: next == nil,
+				},
+			},
+		},
+	}
+
+	return response
+}
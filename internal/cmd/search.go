@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const (
+	CMDSearch          = "search"
+	CMDSearchName      = "name"
+	CMDSearchRarity    = "rarity"
+	CMDSearchMinLevel  = "min-level"
+	CMDSearchMaxLevel  = "max-level"
+	CMDSearchTraits    = "traits"
+	CMDSearchCount     = "count"
+	CMDSearchCurrency  = "output-currency"
+	DefaultSearchCount = 10
+	MaxSearchCount     = 25
+)
+
+func searchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        CMDSearch,
+		Description: "Search heroes by metadata",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSearchName,
+				Description: "Part of the hero name",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSearchRarity,
+				Description: "Hero rarity (Default: All)",
+				Required:    false,
+				Choices:     rarityChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        CMDSearchMinLevel,
+				Description: "Minimum BHQ level",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        CMDSearchMaxLevel,
+				Description: "Maximum BHQ level",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSearchTraits,
+				Description: "Other traits as Key=Value pairs separated by commas",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        CMDSearchCount,
+				Description: fmt.Sprintf("Return this many heroes (Default %v, Max: %v)", DefaultSearchCount, MaxSearchCount),
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSearchCurrency,
+				Description: "Output currency (Default: USD)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "USD", Value: coinbase.FiatUSD},
+					{Name: "EUR", Value: coinbase.FiatEUR},
+					{Name: "GBP", Value: coinbase.FiatGBP},
+				},
+			},
+		},
+	}
+}
+
+func (s *SlashCommands) handleSearch(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	var q index.Query
+	count := DefaultSearchCount
+	currency := coinbase.FiatUSD
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case CMDSearchName:
+			q.Name = option.StringValue()
+		case CMDSearchRarity:
+			q.Rarity = option.StringValue()
+		case CMDSearchMinLevel:
+			q.MinLevel = int(option.IntValue())
+		case CMDSearchMaxLevel:
+			q.MaxLevel = int(option.IntValue())
+		case CMDSearchTraits:
+			traits, err := index.ParseTraits(option.StringValue())
+			if err != nil {
+				return &discordgo.InteractionResponseData{Content: err.Error()}
+			}
+			q.Traits = traits
+		case CMDSearchCount:
+			count = int(option.IntValue())
+		case CMDSearchCurrency:
+			currency = coinbase.FiatSymbol(option.StringValue())
+		}
+	}
+
+	if count > MaxSearchCount {
+		count = MaxSearchCount
+	} else if count < 1 {
+		count = 1
+	}
+
+	logger.Debugf(sess, i.Interaction, "Search heroes for %#v", q)
+	return s.searchHandler.HandleCommand(q, count, currency)
+}
@@ -8,8 +8,13 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/deadloct/bitverse-nft-bot/internal/api"
 	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
 	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
 	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/bitverse-nft-bot/internal/wallets"
 	"github.com/deadloct/immutablex-go-lib/coinbase"
 	"github.com/deadloct/immutablex-go-lib/orders"
 	log "github.com/sirupsen/logrus"
@@ -33,31 +38,78 @@ const (
 	CMDMarketUser             = "user"
 	CMDMarketCount            = "count"
 	CMDMarketTokenID          = "token-id"
+	CMDMarketHero             = "hero"
 	CMDMarketOutputFormat     = "output-format"
 	CMDMarketOutputCurrency   = "output-currency"
 	CMDMarketBuyCurrency      = "buy-currency"
 	CMDMarketAllBuyCurrencies = "All"
+	CMDFloor                  = "floor"
+	CMDFloorCollection        = "collection"
+	CMDFloorOutputCurrency    = "output-currency"
+	CMDFloorBuyCurrency       = "buy-currency"
+	CMDHistory                = "history"
+	CMDHistoryCollection      = "collection"
+	CMDHistoryRarity          = "rarity"
+	CMDHistoryCurrency        = "currency"
+	CMDHistoryCurrencyETH     = "ETH"
+	CMDChart                  = "chart"
+	CMDChartCollection        = "collection"
+	CMDChartRarity            = "rarity"
+	CMDChartCurrency          = "currency"
+	CMDChartPeriod            = "period"
 )
 
 type SlashCommands struct {
+	chartHandler   *handlers.ChartHandler
 	clientsManager *api.ClientsManager
+	compareHandler *handlers.CompareHandler
+	heroIndex      *index.Index
 	heroesHandler  *handlers.AssetMessageHandler
+	historyHandler *handlers.HistoryHandler
+	links          *wallets.Links
+	marketQueries  *marketQueries
 	ordersHandler  *handlers.OrdersHandler
 	portalsHandler *handlers.AssetMessageHandler
-	session        *discordgo.Session
+	searchHandler  *handlers.SearchHandler
+	session        discord.Transport
 	started        bool
+	subs           *notifier.Subscriptions
+	walletHandler  *handlers.WalletHandler
+	watchers       *notifier.Manager
 }
 
-func NewSlashCommands(cm *api.ClientsManager, session *discordgo.Session) *SlashCommands {
+func NewSlashCommands(
+	cm *api.ClientsManager,
+	session discord.Transport,
+	watchers *notifier.Manager,
+	subs *notifier.Subscriptions,
+	historyStore history.Store,
+	heroIndex *index.Index,
+	links *wallets.Links,
+) *SlashCommands {
+	ordersHandler := handlers.NewOrdersHandler(cm, heroIndex)
+
 	return &SlashCommands{
+		chartHandler:   handlers.NewChartHandler(historyStore),
 		clientsManager: cm,
-		heroesHandler:  handlers.NewAssetMessageHandler(data.BitVerseCollections["hero"], cm),
-		ordersHandler:  handlers.NewOrdersHandler(cm),
-		portalsHandler: handlers.NewAssetMessageHandler(data.BitVerseCollections["portal"], cm),
+		compareHandler: handlers.NewCompareHandler(data.BitVerseCollections[data.CollectionHero], cm, ordersHandler),
+		heroIndex:      heroIndex,
+		heroesHandler:  handlers.NewAssetMessageHandler(data.BitVerseCollections[data.CollectionHero], cm, heroIndex),
+		historyHandler: handlers.NewHistoryHandler(historyStore),
+		links:          links,
+		marketQueries:  newMarketQueries(),
+		ordersHandler:  ordersHandler,
+		portalsHandler: handlers.NewAssetMessageHandler(data.BitVerseCollections[data.CollectionPortal], cm, nil),
+		searchHandler:  handlers.NewSearchHandler(data.BitVerseCollections[data.CollectionHero], heroIndex, ordersHandler),
 		session:        session,
+		subs:           subs,
+		walletHandler:  handlers.NewWalletHandler(cm, ordersHandler),
+		watchers:       watchers,
 	}
 }
 
+// Start starts the API clients and, when the transport is a Discord session,
+// opens it and registers the slash commands.
 func (s *SlashCommands) Start() error {
 	if s.started {
 		return nil
@@ -67,17 +119,27 @@ func (s *SlashCommands) Start() error {
 		return err
 	}
 
+	// Other transports, such as a discord.Recorder, have no gateway to
+	// receive interactions from or register commands with.
+	gateway, ok := s.session.(*discordgo.Session)
+	if !ok {
+		s.started = true
+		return nil
+	}
+
 	// SlashCommands command handler
-	s.session.AddHandler(s.commandHandler)
+	gateway.AddHandler(func(sess *discordgo.Session, i *discordgo.InteractionCreate) {
+		s.commandHandler(sess, i)
+	})
 
 	// Open up the session
-	if err := s.session.Open(); err != nil {
+	if err := gateway.Open(); err != nil {
 		s.clientsManager.Stop()
 		return err
 	}
 
 	// Register slash commands
-	s.setupCommands()
+	s.setupCommands(gateway)
 	s.started = true
 	return nil
 }
@@ -87,21 +149,24 @@ func (s *SlashCommands) Stop() {
 		return
 	}
 
-	s.cleanupCommands()
+	if gateway, ok := s.session.(*discordgo.Session); ok {
+		s.cleanupCommands(gateway)
+	}
 	s.clientsManager.Stop()
 }
 
-func (s *SlashCommands) setupCommands() {
+func (s *SlashCommands) setupCommands(gateway *discordgo.Session) {
 	commands := []*discordgo.ApplicationCommand{
 		{
 			Name:        CMDHero,
 			Description: "Fetches the hero NFT with the provided ID",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        CMDHeroID,
-					Description: "The hero ID to retrieve",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         CMDHeroID,
+					Description:  "The hero ID or name to retrieve",
+					Required:     true,
+					Autocomplete: true,
 				},
 			},
 		},
@@ -130,10 +195,7 @@ func (s *SlashCommands) setupCommands() {
 					Name:        CMDMarketCollection,
 					Description: "The collection of returned listings (default: Heroes)",
 					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{Name: "Heroes", Value: data.BitVerseCollections["hero"].Address},
-						{Name: "Portals", Value: data.BitVerseCollections["portal"].Address},
-					},
+					Choices:     collectionChoices(true),
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -153,13 +215,7 @@ func (s *SlashCommands) setupCommands() {
 					Name:        CMDMarketRarity,
 					Description: "Filter by NFT rarity (Default: All)",
 					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{Name: "Common", Value: "Common"},
-						{Name: "Rare", Value: "Rare"},
-						{Name: "Epic", Value: "Epic"},
-						{Name: "Legendary", Value: "Legendary"},
-						{Name: "Mythic", Value: "Mythic"},
-					},
+					Choices:     rarityChoices(),
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
@@ -170,6 +226,7 @@ func (s *SlashCommands) setupCommands() {
 						{Name: "Created At", Value: "created_at"},
 						{Name: "Expired At", Value: "expired_at"},
 						{Name: "Price", Value: "buy_quantity_with_fees"},
+						{Name: "Rarity Rank (Rarest First)", Value: handlers.OrderByRarityRank},
 						{Name: "Updated At", Value: "updated_at"},
 					},
 				},
@@ -186,7 +243,7 @@ func (s *SlashCommands) setupCommands() {
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        CMDMarketUser,
-					Description: "User address that created the order",
+					Description: "User address that created the order, or \"me\" for your linked wallet",
 					Required:    false,
 				},
 				{
@@ -205,6 +262,13 @@ func (s *SlashCommands) setupCommands() {
 					Description: "The token ID of the listing",
 					Required:    false,
 				},
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         CMDMarketHero,
+					Description:  "The hero name or ID of the listing",
+					Required:     false,
+					Autocomplete: true,
+				},
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        CMDMarketOutputFormat,
@@ -239,13 +303,120 @@ func (s *SlashCommands) setupCommands() {
 				},
 			},
 		},
+		{
+			Name:        CMDFloor,
+			Description: "Shows the cheapest listing of each rarity",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDFloorCollection,
+					Description: "The collection to check (Default: Heroes)",
+					Required:    false,
+					Choices:     collectionChoices(false),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDFloorOutputCurrency,
+					Description: "Output currency (Default: USD)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "USD", Value: coinbase.FiatUSD},
+						{Name: "EUR", Value: coinbase.FiatEUR},
+						{Name: "GBP", Value: coinbase.FiatGBP},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDFloorBuyCurrency,
+					Description: "Listing cryptocurrency (Default: ETH)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "ETH", Value: handlers.TokenTypeETH},
+						{Name: "USDC/IMX/Other", Value: handlers.TokenTypeERC20},
+					},
+				},
+			},
+		},
+		{
+			Name:        CMDHistory,
+			Description: "Shows floor price stats over the last 24 hours, 7 days and 30 days",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDHistoryCollection,
+					Description: "The collection to report on (Default: Heroes)",
+					Required:    false,
+					Choices:     collectionChoices(false),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDHistoryRarity,
+					Description: "Only report this rarity (Default: All)",
+					Required:    false,
+					Choices:     rarityChoices(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDHistoryCurrency,
+					Description: "Report prices in ETH or fiat (Default: ETH)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "ETH", Value: CMDHistoryCurrencyETH},
+						{Name: "USD", Value: coinbase.FiatUSD},
+						{Name: "EUR", Value: coinbase.FiatEUR},
+						{Name: "GBP", Value: coinbase.FiatGBP},
+					},
+				},
+			},
+		},
+		{
+			Name:        CMDChart,
+			Description: "Draws a chart of the floor price history",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDChartCollection,
+					Description: "The collection to chart (Default: Heroes)",
+					Required:    false,
+					Choices:     collectionChoices(false),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDChartRarity,
+					Description: "Only chart this rarity (Default: All)",
+					Required:    false,
+					Choices:     rarityChoices(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDChartCurrency,
+					Description: "Chart prices in ETH or fiat (Default: ETH)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "ETH", Value: CMDHistoryCurrencyETH},
+						{Name: "USD", Value: coinbase.FiatUSD},
+						{Name: "EUR", Value: coinbase.FiatEUR},
+						{Name: "GBP", Value: coinbase.FiatGBP},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        CMDChartPeriod,
+					Description: "How far back to chart (Default: 7d)",
+					Required:    false,
+					Choices:     periodChoices(),
+				},
+			},
+		},
 	}
 
+	commands = append(commands, searchCommand(), compareCommand(), walletCommand(), linkWalletCommand(), unlinkWalletCommand(), watchCommand(), subscribeCommand(CMDSubscribe), subscribeCommand(CMDUnsubscribe))
+
 	// Add new commands
 	log.Debug("registering slash commands")
 	for _, v := range commands {
 		log.Debugf("registering command %v", v.Name)
-		if _, err := s.session.ApplicationCommandCreate(s.session.State.User.ID, "", v); err != nil {
+		if _, err := gateway.ApplicationCommandCreate(gateway.State.User.ID, "", v); err != nil {
 			log.Panicf("cannot create command %v: %v", v.Name, err)
 		}
 
@@ -255,8 +426,8 @@ func (s *SlashCommands) setupCommands() {
 	log.Debug("finished registering slash commands")
 }
 
-func (s *SlashCommands) cleanupCommands() {
-	existingCommands, err := s.session.ApplicationCommands(s.session.State.User.ID, "")
+func (s *SlashCommands) cleanupCommands(gateway *discordgo.Session) {
+	existingCommands, err := gateway.ApplicationCommands(gateway.State.User.ID, "")
 	if err != nil {
 		log.Errorf("could not retrieve commands to do a pre-startup cleanup")
 	}
@@ -264,7 +435,7 @@ func (s *SlashCommands) cleanupCommands() {
 	log.Debug("cleaning up old slash commands during startup...")
 	for _, v := range existingCommands {
 		log.Debugf("removing command %v", v.Name)
-		if err := s.session.ApplicationCommandDelete(s.session.State.User.ID, "", v.ID); err != nil {
+		if err := gateway.ApplicationCommandDelete(gateway.State.User.ID, "", v.ID); err != nil {
 			log.Debugf("unable to remove command %v: %v", v.Name, err)
 		} else {
 			log.Debugf("removed command %v", v.Name)
@@ -274,7 +445,16 @@ func (s *SlashCommands) cleanupCommands() {
 	log.Debug("finished old command cleanup")
 }
 
-func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.InteractionCreate) {
+func (s *SlashCommands) commandHandler(sess discord.Transport, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		s.componentHandler(sess, i)
+		return
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		s.autocompleteHandler(sess, i)
+		return
+	}
+
 	var response *discordgo.InteractionResponseData
 
 	sess.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -288,7 +468,7 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 	switch v {
 	case CMDRates:
 		logger.Info(sess, i.Interaction, "Handling rates command")
-		client := coinbase.GetCoinbaseClientInstance()
+		client := s.clientsManager.SpotPriceClient
 
 		cryptos := []coinbase.CryptoSymbol{
 			coinbase.CryptoETH, coinbase.CryptoIMX, coinbase.CryptoUSDC,
@@ -315,8 +495,11 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 
 	case CMDHero:
 		logger.Info(sess, i.Interaction, "Handling hero command")
-		id := options[0].IntValue()
-		response = s.heroesHandler.HandleCommand(fmt.Sprint(id))
+		if id, failed := s.resolveHero(options[0].StringValue()); failed == nil {
+			response = s.heroesHandler.HandleCommand(id)
+		} else {
+			response = failed
+		}
 
 	case CMDPortal:
 		logger.Info(sess, i.Interaction, "Handling portal command")
@@ -328,7 +511,7 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 		cfg := &orders.ListOrdersConfig{
 			BuyTokenType:     handlers.TokenTypeETH,
 			PageSize:         DefaultOrderCount,
-			SellTokenAddress: data.BitVerseCollections["hero"].Address,
+			SellTokenAddress: data.BitVerseCollections[data.CollectionHero].Address,
 			Status:           "active",
 			OrderBy:          "buy_quantity_with_fees",
 			Direction:        "asc",
@@ -337,12 +520,13 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 		format := "summary"
 		currency := coinbase.FiatUSD
 		metadata := make(map[string][]string)
+		var hero, rarity string
 		for _, option := range options {
 			switch option.Name {
 			case CMDMarketCollection:
 				cfg.SellTokenAddress = option.StringValue()
 				if cfg.SellTokenAddress == "" {
-					cfg.SellTokenAddress = data.BitVerseCollections["hero"].Address
+					cfg.SellTokenAddress = data.BitVerseCollections[data.CollectionHero].Address
 				}
 
 			case CMDMarketCount:
@@ -367,7 +551,7 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 				}
 
 			case CMDMarketRarity:
-				metadata["Rarity"] = []string{option.StringValue()}
+				rarity = option.StringValue()
 
 			case CMDMarketOutputFormat:
 				format = option.StringValue()
@@ -392,12 +576,47 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 
 			case CMDMarketUser:
 				cfg.User = option.StringValue()
+				if cfg.User == LinkedWalletAlias {
+					address, ok := s.links.Get(interactionUserID(i.Interaction))
+					if !ok {
+						response = &discordgo.InteractionResponseData{Content: NoLinkedWalletMessage}
+					}
+					cfg.User = address
+				}
+
+			case CMDMarketHero:
+				hero = option.StringValue()
 
 			default:
 				continue
 			}
 		}
 
+		if response != nil {
+			break
+		}
+
+		if hero != "" {
+			id, failed := s.resolveHero(hero)
+			if failed != nil {
+				response = failed
+				break
+			}
+
+			cfg.SellTokenAddress = data.BitVerseCollections[data.CollectionHero].Address
+			cfg.SellTokenID = id
+		}
+
+		// The rarity key depends on the collection, which may come later in
+		// the options.
+		if rarity != "" {
+			key := data.MetadataRarity
+			if col, ok := data.CollectionByAddress(cfg.SellTokenAddress); ok {
+				key = col.RarityKey()
+			}
+			metadata[key] = []string{rarity}
+		}
+
 		if cfg.PageSize > MaxOrderCount && format != "summary" {
 			cfg.PageSize = MaxOrderCount
 		} else if cfg.PageSize < 1 {
@@ -414,7 +633,98 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 		}
 
 		logger.Debugf(sess, i.Interaction, "Get orders for cfg %#v", cfg)
-		response = s.ordersHandler.HandleCommand(cfg, format, currency)
+		response = s.handleMarket(i, cfg, format, currency)
+
+	case CMDFloor:
+		logger.Info(sess, i.Interaction, "Handling floor command")
+		col := data.BitVerseCollections[data.CollectionHero]
+		buyTokenType := handlers.TokenTypeETH
+		currency := coinbase.FiatUSD
+		for _, option := range options {
+			switch option.Name {
+			case CMDFloorCollection:
+				if c, ok := data.BitVerseCollections[option.StringValue()]; ok {
+					col = c
+				}
+			case CMDFloorOutputCurrency:
+				currency = coinbase.FiatSymbol(option.StringValue())
+			case CMDFloorBuyCurrency:
+				buyTokenType = option.StringValue()
+			}
+		}
+
+		response = s.ordersHandler.HandleFloorCommand(col, buyTokenType, currency)
+
+	case CMDHistory:
+		logger.Info(sess, i.Interaction, "Handling history command")
+		collection := data.CollectionHero
+		var rarity string
+		var fiat coinbase.FiatSymbol
+		for _, option := range options {
+			switch option.Name {
+			case CMDHistoryCollection:
+				collection = option.StringValue()
+			case CMDHistoryRarity:
+				rarity = option.StringValue()
+			case CMDHistoryCurrency:
+				if v := option.StringValue(); v != CMDHistoryCurrencyETH {
+					fiat = coinbase.FiatSymbol(v)
+				}
+			}
+		}
+
+		response = s.historyHandler.HandleCommand(collection, rarity, fiat)
+
+	case CMDChart:
+		logger.Info(sess, i.Interaction, "Handling chart command")
+		collection := data.CollectionHero
+		var rarity string
+		var fiat coinbase.FiatSymbol
+		window := history.Windows[1]
+		for _, option := range options {
+			switch option.Name {
+			case CMDChartCollection:
+				collection = option.StringValue()
+			case CMDChartRarity:
+				rarity = option.StringValue()
+			case CMDChartCurrency:
+				if v := option.StringValue(); v != CMDHistoryCurrencyETH {
+					fiat = coinbase.FiatSymbol(v)
+				}
+			case CMDChartPeriod:
+				for _, w := range history.Windows {
+					if w.Name == option.StringValue() {
+						window = w
+					}
+				}
+			}
+		}
+
+		response = s.chartHandler.HandleCommand(collection, rarity, fiat, window)
+
+	case CMDSearch:
+		logger.Info(sess, i.Interaction, "Handling search command")
+		response = s.handleSearch(sess, i)
+
+	case CMDCompare:
+		logger.Info(sess, i.Interaction, "Handling compare command")
+		response = s.handleCompare(sess, i)
+
+	case CMDWallet:
+		logger.Info(sess, i.Interaction, "Handling wallet command")
+		response = s.handleWallet(sess, i)
+
+	case CMDLinkWallet, CMDUnlinkWallet:
+		logger.Infof(sess, i.Interaction, "Handling %s command", v)
+		response = s.handleLinkWallet(sess, i)
+
+	case CMDWatch:
+		logger.Info(sess, i.Interaction, "Handling watch command")
+		response = s.handleWatch(sess, i)
+
+	case CMDSubscribe, CMDUnsubscribe:
+		logger.Infof(sess, i.Interaction, "Handling %s command", v)
+		response = s.handleSubscribe(sess, i)
 
 	default:
 		logger.Warnf(sess, i.Interaction, "Unknown command: %s", v)
@@ -423,11 +733,66 @@ func (s *SlashCommands) commandHandler(sess *discordgo.Session, i *discordgo.Int
 		}
 	}
 
-	_, err := sess.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+	s.editResponse(sess, i, response)
+}
+
+func (s *SlashCommands) componentHandler(sess discord.Transport, i *discordgo.InteractionCreate) {
+	sess.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	var response *discordgo.InteractionResponseData
+
+	id := i.MessageComponentData().CustomID
+	switch {
+	case strings.HasPrefix(id, MarketButtonPrefix+":"):
+		logger.Info(sess, i.Interaction, "Handling market button")
+		response = s.handleMarketButton(sess, i)
+
+	default:
+		logger.Warnf(sess, i.Interaction, "Unknown component: %s", id)
+		return
+	}
+
+	s.editResponse(sess, i, response)
+}
+
+func (s *SlashCommands) editResponse(sess discord.Transport, i *discordgo.InteractionCreate, response *discordgo.InteractionResponseData) {
+	edit := &discordgo.WebhookEdit{
 		Content: &response.Content,
 		Embeds:  &response.Embeds,
-	})
+		Files:   response.Files,
+	}
+	if response.Components != nil {
+		edit.Components = &response.Components
+	}
+
+	_, err := sess.InteractionResponseEdit(i.Interaction, edit)
 	if err != nil {
 		logger.Error(sess, i.Interaction, err)
 	}
 }
+
+// collectionChoices lists the registered collections, valued by token address
+// or by registry key.
+func collectionChoices(byAddress bool) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, col := range data.Collections() {
+		value := col.Key
+		if byAddress {
+			value = col.Address
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: col.Name, Value: value})
+	}
+
+	return choices
+}
+
+func periodChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, w := range history.Windows {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: w.Name, Value: w.Name})
+	}
+
+	return choices
+}
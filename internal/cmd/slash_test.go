@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const testOwner = "0x2222222222222222222222222222222222222222"
+
+// newTestSlashCommands lists three heroes, hero 3 the cheapest and the only
+// rare one, and records the responses instead of sending them.
+func newTestSlashCommands() (*SlashCommands, *discord.Recorder) {
+	address := data.BitVerseCollections[data.CollectionHero].Address
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	assets := fake.NewAssets()
+	list := fake.NewOrders(assets)
+	for i := 1; i <= 3; i++ {
+		tokenID := fmt.Sprint(i)
+		rarity := "Common"
+		if i == 3 {
+			rarity = "Rare"
+		}
+
+		assets.Put(fake.Asset(address, tokenID, testOwner, map[string]interface{}{
+			data.MetadataRarity:   rarity,
+			data.MetadataHeroName: "Hero " + tokenID,
+		}))
+		list.Put(fake.Order(int32(i), address, tokenID, float64(4-i)/100, start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	spot := fake.NewSpotPrices()
+	spot.Set(coinbase.CryptoETH, coinbase.FiatUSD, 2000)
+	spot.Set(coinbase.CryptoETH, coinbase.FiatGBP, 1600)
+	spot.Set(coinbase.CryptoETH, coinbase.FiatEUR, 1800)
+
+	recorder := discord.NewRecorder()
+	s := NewSlashCommands(fake.NewClientsManager(list, assets, spot), recorder, nil, nil, nil, nil, nil)
+	return s, recorder
+}
+
+func command(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        "interaction-" + name,
+			Type:      discordgo.InteractionApplicationCommand,
+			GuildID:   "guild",
+			ChannelID: "channel",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "user"}},
+			Data:      discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+		},
+	}
+}
+
+func button(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        "button-" + customID,
+			Type:      discordgo.InteractionMessageComponent,
+			GuildID:   "guild",
+			ChannelID: "channel",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "user"}},
+			Data:      discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+		},
+	}
+}
+
+func stringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
+
+func intOption(name string, value int) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionInteger, Value: float64(value)}
+}
+
+// editText joins the content and every embed title, description and field of
+// the edit.
+func editText(edit *discordgo.WebhookEdit) string {
+	var parts []string
+	if edit.Content != nil {
+		parts = append(parts, *edit.Content)
+	}
+	if edit.Embeds != nil {
+		for _, e := range *edit.Embeds {
+			parts = append(parts, e.Title, e.Description)
+			for _, f := range e.Fields {
+				parts = append(parts, f.Name, f.Value)
+			}
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func TestCommandHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.InteractionCreate
+		embeds      int
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:        "rates",
+			interaction: command(CMDRates),
+			contains:    []string{"1 ETH ≈ $2000.00 ≈ £1600.00 ≈ €1800.00"},
+		},
+		{
+			name:        "hero",
+			interaction: command(CMDHero, stringOption(CMDHeroID, "3")),
+			embeds:      1,
+			contains:    []string{"Item #3", "Rare", testOwner},
+		},
+		{
+			name:        "hero not found",
+			interaction: command(CMDHero, stringOption(CMDHeroID, "99")),
+			contains:    []string{"Error retrieving Hero for token ID 99"},
+		},
+		{
+			name:        "market summary cheapest first",
+			interaction: command(CMDMarket, intOption(CMDMarketCount, 2)),
+			contains:    []string{"Item #3", "Item #2", "$20.00"},
+			excludes:    []string{"Item #1"},
+		},
+		{
+			name:        "market by rarity",
+			interaction: command(CMDMarket, stringOption(CMDMarketRarity, "Common"), stringOption(CMDMarketOutputFormat, "detailed")),
+			embeds:      2,
+			contains:    []string{"Item #2", "Item #1"},
+			excludes:    []string{"Item #3"},
+		},
+		{
+			name:        "floor",
+			interaction: command(CMDFloor),
+			embeds:      1,
+			contains:    []string{"Floor Prices", "0.020000 ETH / $40.00\nHero 2", "0.010000 ETH / $20.00\nHero 3", "No active listings"},
+		},
+		{
+			name:        "unknown command",
+			interaction: command("nope"),
+			contains:    []string{"name nope is unrecognized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, recorder := newTestSlashCommands()
+			s.commandHandler(recorder, tt.interaction)
+
+			if len(recorder.Responses) != 1 || recorder.Responses[0].Response.Type != discordgo.InteractionResponseChannelMessageWithSource {
+				t.Fatalf("got responses %#v, want one loading message", recorder.Responses)
+			}
+
+			edit := recorder.LastEdit()
+			if edit == nil {
+				t.Fatal("the response was never edited")
+			}
+			if got := len(*edit.Embeds); got != tt.embeds {
+				t.Errorf("got %v embeds, want %v", got, tt.embeds)
+			}
+
+			text := editText(edit)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("%q missing from:\n%v", want, text)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("%q in:\n%v", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestMarketButtons(t *testing.T) {
+	s, recorder := newTestSlashCommands()
+	s.commandHandler(recorder, command(CMDMarket, intOption(CMDMarketCount, 1)))
+
+	var pages []string
+	for page := 0; page < 3; page++ {
+		edit := recorder.LastEdit()
+		pages = append(pages, *edit.Content)
+		if edit.Components == nil || len(*edit.Components) != 1 {
+			t.Fatalf("page %v has no buttons:\n%v", page, *edit.Content)
+		}
+
+		buttons := (*edit.Components)[0].(discordgo.ActionsRow).Components
+		previous, next := buttons[0].(discordgo.Button), buttons[1].(discordgo.Button)
+		if previous.Disabled != (page == 0) {
+			t.Errorf("page %v has Previous disabled %v", page, previous.Disabled)
+		}
+		if next.Disabled != (page == 2) {
+			t.Errorf("page %v has Next disabled %v", page, next.Disabled)
+		}
+		if next.Disabled {
+			break
+		}
+
+		s.commandHandler(recorder, button(next.CustomID))
+	}
+
+	for i, want := range []string{"Item #3", "Item #2", "Item #1"} {
+		if i >= len(pages) || !strings.Contains(pages[i], want) {
+			t.Errorf("page %v is not %v:\n%v", i, want, strings.Join(pages, "\n---\n"))
+		}
+	}
+}
+
+func watchList() *discordgo.InteractionCreate {
+	return command(CMDWatch, &discordgo.ApplicationCommandInteractionDataOption{
+		Name: CMDWatchList,
+		Type: discordgo.ApplicationCommandOptionSubCommand,
+	})
+}
+
+func TestWatchListFitsInOneMessage(t *testing.T) {
+	s, recorder := newTestSlashCommands()
+	subs, err := notifier.NewSubscriptions(filepath.Join(t.TempDir(), notifier.DefaultSubscriptionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.watchers = notifier.NewManager(s.clientsManager, recorder, notifier.NewMemorySeenStore(notifier.DefaultSeenTTL), subs, nil, "")
+	defer s.watchers.Stop()
+
+	for n := 0; n < 100; n++ {
+		cfg := notifier.WatcherConfig{
+			Name:      fmt.Sprintf("a-rather-long-watcher-name-%03d", n),
+			Rarity:    []string{"Common", "Rare", "Epic"},
+			Threshold: 250,
+		}
+		if _, err := s.watchers.Add(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.commandHandler(recorder, watchList())
+
+	content := *recorder.LastEdit().Content
+	if len(content) > handlers.MaxContentLength {
+		t.Errorf("got %v characters, want at most %v", len(content), handlers.MaxContentLength)
+	}
+	if !strings.HasPrefix(content, "100 watchers:") || !strings.Contains(content, "a-rather-long-watcher-name-000") {
+		t.Errorf("list does not start with the first watcher:\n%v", content)
+	}
+
+	shown := strings.Count(content, "\n• ")
+	if want := fmt.Sprintf("…and %v more", 100-shown); !strings.HasSuffix(content, want) {
+		t.Errorf("list of %v watchers does not end with %q:\n%v", shown, want, content)
+	}
+}
+
+func TestResolveHero(t *testing.T) {
+	address := data.BitVerseCollections[data.CollectionHero].Address
+	assets := fake.NewAssets()
+	for id, name := range map[string]string{"1": "Alice", "2": "Al", "3": "Alan", "4": "Bob", "5": "bob"} {
+		assets.Put(fake.Asset(address, id, testOwner, map[string]interface{}{data.MetadataHeroName: name}))
+	}
+
+	idx := index.New(fake.NewClientsManager(nil, assets, nil), data.BitVerseCollections[data.CollectionHero], filepath.Join(t.TempDir(), index.DefaultHeroesFile))
+	if err := idx.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		index *index.Index
+		id    string
+		reply []string
+	}{
+		{name: "token ID", value: "42", index: idx, id: "42"},
+		{name: "exact name", value: "alice", index: idx, id: "1"},
+		{name: "exact name that prefixes others", value: "AL", index: idx, id: "2"},
+		{name: "partial name", value: "Ali", index: idx, reply: []string{"Ali matches more than one hero", "Alice (#1)"}},
+		{name: "shared name", value: "Bob", index: idx, reply: []string{"Bob (#4)", "bob (#5)"}},
+		{name: "unknown name", value: "Zed", index: idx, reply: []string{"Could not find a hero named Zed"}},
+		{name: "token ID without index", value: "42", id: "42"},
+		{name: "name without index", value: "Alice", reply: []string{"use a token ID instead of Alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestSlashCommands()
+			s.heroIndex = tt.index
+
+			id, failed := s.resolveHero(tt.value)
+			if tt.reply == nil {
+				if failed != nil || id != tt.id {
+					t.Fatalf("got %q, %v, want %q", id, failed, tt.id)
+				}
+				return
+			}
+
+			if failed == nil {
+				t.Fatalf("got %q, want a reply", id)
+			}
+			for _, want := range tt.reply {
+				if !strings.Contains(failed.Content, want) {
+					t.Errorf("%q missing from %q", want, failed.Content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+)
+
+const (
+	CMDSubscribe            = "subscribe"
+	CMDUnsubscribe          = "unsubscribe"
+	CMDSubscribeWatcher     = "watcher"
+	CMDSubscribeDestination = "destination"
+)
+
+func subscribeCommand(name string) *discordgo.ApplicationCommand {
+	verb := "Receive"
+	if name == CMDUnsubscribe {
+		verb = "Stop receiving"
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        name,
+		Description: fmt.Sprintf("%s notifications from a watcher", verb),
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSubscribeWatcher,
+				Description: "The watcher name or ID shown by /watch list",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDSubscribeDestination,
+				Description: "Direct messages or this channel (Default: DM)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Direct Message", Value: CMDWatchDestinationDM},
+					{Name: "This Channel", Value: CMDWatchDestinationHere},
+				},
+			},
+		},
+	}
+}
+
+func (s *SlashCommands) handleSubscribe(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	data := i.ApplicationCommandData()
+	userID := interactionUserID(i.Interaction)
+
+	var name string
+	destination := CMDWatchDestinationDM
+	for _, option := range data.Options {
+		switch option.Name {
+		case CMDSubscribeWatcher:
+			name = option.StringValue()
+		case CMDSubscribeDestination:
+			destination = option.StringValue()
+		}
+	}
+
+	if _, err := s.watchers.Lookup(name, i.GuildID); err != nil {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("No watcher named %s, see /watch list", name)}
+	}
+
+	subscribe := data.Name == CMDSubscribe
+
+	var err error
+	var target string
+	if destination == CMDWatchDestinationHere {
+		// Channel subscriptions are visible to everyone, so require the same
+		// permission as editing the channel.
+		if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageChannels == 0 {
+			return &discordgo.InteractionResponseData{Content: "You need the Manage Channels permission to change channel subscriptions"}
+		}
+
+		target = fmt.Sprintf("<#%s>", i.ChannelID)
+		if subscribe {
+			err = s.subs.SubscribeChannel(name, i.ChannelID)
+		} else {
+			err = s.subs.UnsubscribeChannel(name, i.ChannelID)
+		}
+	} else {
+		target = fmt.Sprintf("<@%s>", userID)
+		if subscribe {
+			err = s.subs.SubscribeUser(name, userID)
+		} else {
+			err = s.subs.UnsubscribeUser(name, userID)
+		}
+	}
+
+	if err != nil {
+		logger.Errorf(sess, i.Interaction, "could not update subscriptions for watcher %v: %v", name, err)
+		return &discordgo.InteractionResponseData{Content: "Unable to save the subscription, try again later"}
+	}
+
+	if subscribe {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Subscribed %s to watcher %s", target, name)}
+	}
+
+	return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unsubscribed %s from watcher %s", target, name)}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const (
+	CMDWallet         = "wallet"
+	CMDWalletAddress  = "address"
+	CMDWalletCurrency = "output-currency"
+	CMDWalletMode     = "mode"
+	CMDWalletListings = "use-listings"
+
+	WalletModeInventory = "inventory"
+	WalletModeValue     = "value"
+
+	CMDLinkWallet        = "link-wallet"
+	CMDLinkWalletAddress = "address"
+	CMDUnlinkWallet      = "unlink-wallet"
+
+	// LinkedWalletAlias stands for the invoking user's linked wallet wherever
+	// an address is expected.
+	LinkedWalletAlias     = "me"
+	NoLinkedWalletMessage = "You have not linked a wallet yet, use /link-wallet first"
+)
+
+func walletCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        CMDWallet,
+		Description: "List or value the heroes and portals owned by a wallet",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDWalletAddress,
+				Description: "The IMX wallet address (Default: your linked wallet)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDWalletCurrency,
+				Description: "Output currency of listing prices (Default: USD)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "USD", Value: coinbase.FiatUSD},
+					{Name: "EUR", Value: coinbase.FiatEUR},
+					{Name: "GBP", Value: coinbase.FiatGBP},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDWalletMode,
+				Description: "Show the inventory or estimate its value (Default: Inventory)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Inventory", Value: WalletModeInventory},
+					{Name: "Value against floors", Value: WalletModeValue},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        CMDWalletListings,
+				Description: "Value listed NFTs at their own listing price (Default: False)",
+				Required:    false,
+			},
+		},
+	}
+}
+
+func (s *SlashCommands) handleWallet(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	address := LinkedWalletAlias
+	currency := coinbase.FiatUSD
+	mode := WalletModeInventory
+	var useListings bool
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case CMDWalletAddress:
+			address = option.StringValue()
+		case CMDWalletCurrency:
+			currency = coinbase.FiatSymbol(option.StringValue())
+		case CMDWalletMode:
+			mode = option.StringValue()
+		case CMDWalletListings:
+			useListings = option.BoolValue()
+		}
+	}
+
+	if address == LinkedWalletAlias {
+		linked, ok := s.links.Get(interactionUserID(i.Interaction))
+		if !ok {
+			return &discordgo.InteractionResponseData{Content: NoLinkedWalletMessage}
+		}
+		address = linked
+	}
+
+	if mode == WalletModeValue {
+		logger.Debugf(sess, i.Interaction, "Value inventory of %v (listings: %v)", address, useListings)
+		return s.walletHandler.HandleValueCommand(address, currency, useListings)
+	}
+
+	logger.Debugf(sess, i.Interaction, "Get inventory of %v", address)
+	return s.walletHandler.HandleCommand(address, currency)
+}
+
+func linkWalletCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        CMDLinkWallet,
+		Description: "Link your IMX wallet so commands can default to \"me\"",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        CMDLinkWalletAddress,
+				Description: "Your IMX wallet address (0x...)",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func unlinkWalletCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        CMDUnlinkWallet,
+		Description: "Forget your linked IMX wallet",
+	}
+}
+
+func (s *SlashCommands) handleLinkWallet(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	data := i.ApplicationCommandData()
+	userID := interactionUserID(i.Interaction)
+
+	if data.Name == CMDUnlinkWallet {
+		unlinked, err := s.links.Unlink(userID)
+		if err != nil {
+			logger.Errorf(sess, i.Interaction, "could not unlink wallet of %v: %v", userID, err)
+			return &discordgo.InteractionResponseData{Content: "Unable to unlink your wallet, try again later"}
+		}
+
+		if !unlinked {
+			return &discordgo.InteractionResponseData{Content: "You do not have a linked wallet"}
+		}
+
+		return &discordgo.InteractionResponseData{Content: "Unlinked your wallet"}
+	}
+
+	address := data.Options[0].StringValue()
+	if !handlers.IsAddress(address) {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("%s is not a valid wallet address", address)}
+	}
+
+	if err := s.links.Link(userID, address); err != nil {
+		logger.Errorf(sess, i.Interaction, "could not link wallet %v to %v: %v", address, userID, err)
+		return &discordgo.InteractionResponseData{Content: "Unable to link your wallet, try again later"}
+	}
+
+	return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Linked <%s> to your account", handlers.GetImmutascanUserURL(address))}
+}
@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/logger"
+	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+const (
+	CMDWatch                = "watch"
+	CMDWatchAdd             = "add"
+	CMDWatchList            = "list"
+	CMDWatchRemove          = "remove"
+	CMDWatchPause           = "pause"
+	CMDWatchResume          = "resume"
+	CMDWatchID              = "id"
+	CMDWatchCollection      = "collection"
+	CMDWatchRarity          = "rarity"
+	CMDWatchThreshold       = "threshold"
+	CMDWatchCurrency        = "currency"
+	CMDWatchBuyCurrency     = "buy-currency"
+	CMDWatchDestination     = "destination"
+	CMDWatchDestinationDM   = "dm"
+	CMDWatchDestinationHere = "channel"
+	CMDWatchMode            = "mode"
+	CMDWatchMinDrop         = "min-drop"
+	CMDWatchMinDiscount     = "min-discount"
+	DefaultWatchMinDiscount = 20
+)
+
+func watchCommand() *discordgo.ApplicationCommand {
+	idOption := []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        CMDWatchID,
+			Description: "The watcher ID shown by /watch list",
+			Required:    true,
+		},
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        CMDWatch,
+		Description: "Manage your price watchers",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        CMDWatchAdd,
+				Description: "Notify you when the cheapest listing drops below a price",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionNumber,
+						Name:        CMDWatchThreshold,
+						Description: "Notify at or below this fiat price with fees (0 for any price, except in cheapest mode)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchCollection,
+						Description: "The collection to watch (Default: Heroes)",
+						Required:    false,
+						Choices:     collectionChoices(false),
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchRarity,
+						Description: "Only watch this rarity (Default: All)",
+						Required:    false,
+						Choices:     rarityChoices(),
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchCurrency,
+						Description: "Currency of the threshold (Default: USD)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "USD", Value: coinbase.FiatUSD},
+							{Name: "EUR", Value: coinbase.FiatEUR},
+							{Name: "GBP", Value: coinbase.FiatGBP},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchBuyCurrency,
+						Description: "Listing cryptocurrency (Default: ETH)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "ETH", Value: handlers.TokenTypeETH},
+							{Name: "USDC/IMX/Other", Value: handlers.TokenTypeERC20},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchMode,
+						Description: "What to notify about (Default: Cheapest listing)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Cheapest listing", Value: notifier.ModeCheapest},
+							{Name: "Every new listing", Value: notifier.ModeListings},
+							{Name: "Price drops on relisted tokens", Value: notifier.ModePriceDrops},
+							{Name: "Listings below fair value", Value: notifier.ModeUnderpriced},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionNumber,
+						Name:        CMDWatchMinDrop,
+						Description: "Minimum price drop percentage for the price drops mode (Default: 0)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionNumber,
+						Name:        CMDWatchMinDiscount,
+						Description: "Percentage below fair value for the fair value mode (Default: 20)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        CMDWatchDestination,
+						Description: "Where to send notifications (Default: DM)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Direct Message", Value: CMDWatchDestinationDM},
+							{Name: "This Channel", Value: CMDWatchDestinationHere},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        CMDWatchList,
+				Description: "List the watchers available in this server",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        CMDWatchRemove,
+				Description: "Delete one of your watchers",
+				Options:     idOption,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        CMDWatchPause,
+				Description: "Stop one of your watchers without deleting it",
+				Options:     idOption,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        CMDWatchResume,
+				Description: "Restart one of your paused watchers",
+				Options:     idOption,
+			},
+		},
+	}
+}
+
+// rarityChoices lists the rarities of every registered collection, up to the
+// number of choices Discord allows.
+func rarityChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, r := range data.AllRarities() {
+		if len(choices) == MaxAutocompleteChoices {
+			break
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: r, Value: r})
+	}
+
+	return choices
+}
+
+func (s *SlashCommands) handleWatch(sess discord.Transport, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return &discordgo.InteractionResponseData{Content: "Missing watch subcommand"}
+	}
+
+	sub := options[0]
+	userID := interactionUserID(i.Interaction)
+
+	switch sub.Name {
+	case CMDWatchAdd:
+		cfg := notifier.WatcherConfig{
+			Collection:     data.CollectionHero,
+			GuildID:        i.GuildID,
+			MinDiscountPct: DefaultWatchMinDiscount,
+			OwnerID:        userID,
+		}
+
+		destination := CMDWatchDestinationDM
+		for _, option := range sub.Options {
+			switch option.Name {
+			case CMDWatchThreshold:
+				cfg.Threshold = option.FloatValue()
+			case CMDWatchCollection:
+				cfg.Collection = option.StringValue()
+			case CMDWatchRarity:
+				cfg.Rarity = []string{option.StringValue()}
+			case CMDWatchCurrency:
+				cfg.Currency = coinbase.FiatSymbol(option.StringValue())
+			case CMDWatchBuyCurrency:
+				cfg.BuyTokenType = option.StringValue()
+			case CMDWatchMode:
+				cfg.Mode = option.StringValue()
+			case CMDWatchMinDrop:
+				cfg.MinDropPct = option.FloatValue()
+			case CMDWatchMinDiscount:
+				cfg.MinDiscountPct = option.FloatValue()
+			case CMDWatchDestination:
+				destination = option.StringValue()
+			}
+		}
+
+		if destination == CMDWatchDestinationHere {
+			cfg.Channels = []string{i.ChannelID}
+		} else {
+			cfg.Users = []string{userID}
+		}
+
+		w, err := s.watchers.Add(cfg)
+		if err != nil {
+			logger.Errorf(sess, i.Interaction, "could not add watcher %#v: %v", cfg, err)
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to create watcher: %v", err)}
+		}
+
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Created watcher %s", describeWatcher(w))}
+
+	case CMDWatchList:
+		watchers := s.watchers.List(i.GuildID)
+		if len(watchers) == 0 {
+			return &discordgo.InteractionResponseData{Content: "No watchers are available in this server"}
+		}
+
+		// Leave room for the "and N more" line when the list is too long.
+		content := fmt.Sprintf("%v watchers:", len(watchers))
+		for n, w := range watchers {
+			line := "\n• " + describeWatcher(w)
+			if len(content)+len(line) > handlers.MaxContentLength-32 {
+				content += fmt.Sprintf("\n…and %v more", len(watchers)-n)
+				break
+			}
+
+			content += line
+		}
+
+		return &discordgo.InteractionResponseData{Content: content}
+
+	case CMDWatchRemove, CMDWatchPause, CMDWatchResume:
+		id := sub.Options[0].StringValue()
+
+		var err error
+		var verb string
+		switch sub.Name {
+		case CMDWatchRemove:
+			err = s.watchers.Remove(id, userID)
+			verb = "Removed"
+		case CMDWatchPause:
+			err = s.watchers.Pause(id, userID)
+			verb = "Paused"
+		case CMDWatchResume:
+			err = s.watchers.Resume(id, userID)
+			verb = "Resumed"
+		}
+
+		switch {
+		case errors.Is(err, notifier.ErrWatcherNotFound):
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("No watcher with ID %s", id)}
+		case errors.Is(err, notifier.ErrNotWatcherOwner):
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Watcher %s belongs to someone else", id)}
+		case err != nil:
+			logger.Errorf(sess, i.Interaction, "could not %s watcher %v: %v", sub.Name, id, err)
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to %s watcher %s", sub.Name, id)}
+		}
+
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("%s watcher %s", verb, id)}
+
+	default:
+		logger.Warnf(sess, i.Interaction, "Unknown watch subcommand: %s", sub.Name)
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("subcommand %s is unrecognized", sub.Name)}
+	}
+}
+
+func describeWatcher(w *notifier.Watcher) string {
+	cfg := w.Config()
+
+	rarity := "All"
+	if len(cfg.Rarity) > 0 {
+		rarity = strings.Join(cfg.Rarity, "/")
+	}
+
+	status := "active"
+	if !w.Started() {
+		status = "paused"
+	}
+
+	what := "Cheapest"
+	switch cfg.Mode {
+	case notifier.ModeListings:
+		what = "New listings of"
+	case notifier.ModeSales:
+		what = "Sales of"
+	case notifier.ModePriceDrops:
+		what = fmt.Sprintf("Price drops of at least %v%% on", cfg.MinDropPct)
+	case notifier.ModeUnderpriced:
+		what = fmt.Sprintf("Listings at least %v%% below fair value of", cfg.MinDiscountPct)
+	}
+
+	str := fmt.Sprintf("%s: %s %s %s", cfg.Name, what, rarity, data.BitVerseCollections[cfg.Collection].Name)
+	if cfg.Threshold > 0 {
+		str += " at or below " + handlers.FormatPrice(cfg.Threshold, cfg.Currency)
+	}
+	if cfg.BuyTokenType == notifier.BuyTokenTypeAll {
+		str += " in any currency"
+	} else {
+		str += " in " + cfg.BuyTokenType
+	}
+
+	if cfg.OwnerID == "" {
+		return fmt.Sprintf("%s (%s)", str, status)
+	}
+
+	destination := "DM"
+	if len(cfg.Channels) > 0 {
+		destination = fmt.Sprintf("<#%s>", cfg.Channels[0])
+	}
+
+	return fmt.Sprintf("#%s, owner <@%s>, sent to %s (%s)", str, cfg.OwnerID, destination, status)
+}
+
+// interactionUserID returns the invoking user in both guilds and DMs.
+func interactionUserID(i *discordgo.Interaction) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+
+	if i.User != nil {
+		return i.User.ID
+	}
+
+	return ""
+}
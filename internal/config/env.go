@@ -31,6 +31,28 @@ func EnvKey(str string) string {
 	return fmt.Sprintf("%s_%s", Prefix, str)
 }
 
+// FilePath resolves filename relative to the directory of the running
+// executable, the same place the .env files are loaded from. Absolute paths are
+// returned unchanged.
+func FilePath(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+
+	return envPath(filename)
+}
+
+// DataPath resolves filename inside the DATA_DIR directory (default "data"
+// next to the executable), where the bot keeps its persistent state.
+func DataPath(filename string) string {
+	dir := GetenvStr("DATA_DIR")
+	if dir == "" {
+		dir = "data"
+	}
+
+	return FilePath(filepath.Join(dir, filename))
+}
+
 func envPath(filename string) string {
 	p, err := os.Executable()
 	if err != nil {
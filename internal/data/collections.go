@@ -1,20 +1,256 @@
 package data
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	DefaultCollectionsFile = "collections.json"
+
+	// CollectionHero and CollectionPortal are the keys of the collections
+	// with dedicated commands such as /hero and /portal.
+	CollectionHero   = "hero"
+	CollectionPortal = "portal"
+)
+
+// RequiredCollections must be in every collections file, since the commands
+// and the hero index built around them have no other collection to fall back
+// to.
+var RequiredCollections = []string{CollectionHero, CollectionPortal}
+
+// MetadataKeys names the metadata fields of a collection. Empty fields fall
+// back to the BitVerse Heroes names.
+type MetadataKeys struct {
+	Rarity   string `json:"rarity,omitempty"`
+	HeroName string `json:"hero_name,omitempty"`
+	Level    string `json:"level,omitempty"`
+	// GamePrefix starts the keys of traits that change as the game is
+	// played, and NonTraits are keys describing the token rather than a
+	// trait. Neither count towards rarity scores.
+	GamePrefix string   `json:"game_prefix,omitempty"`
+	NonTraits  []string `json:"non_traits,omitempty"`
+}
+
 type BitVerseCollection struct {
-	Singular string
-	Name     string
-	Address  string
+	Key      string `json:"key"`
+	Singular string `json:"singular"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	// TokenTroveSlug is the collection name in TokenTrove asset URLs. When it
+	// is empty TokenTroveNote is shown instead of a link.
+	TokenTroveSlug string       `json:"tokentrove_slug,omitempty"`
+	TokenTroveNote string       `json:"tokentrove_note,omitempty"`
+	Metadata       MetadataKeys `json:"metadata,omitempty"`
+	// Rarities are the values of the rarity trait from most to least common.
+	// Empty falls back to the BitVerse rarities.
+	Rarities []string `json:"rarities,omitempty"`
+}
+
+func (c BitVerseCollection) RarityKey() string {
+	if c.Metadata.Rarity != "" {
+		return c.Metadata.Rarity
+	}
+
+	return MetadataRarity
+}
+
+func (c BitVerseCollection) HeroNameKey() string {
+	if c.Metadata.HeroName != "" {
+		return c.Metadata.HeroName
+	}
+
+	return MetadataHeroName
+}
+
+func (c BitVerseCollection) LevelKey() string {
+	if c.Metadata.Level != "" {
+		return c.Metadata.Level
+	}
+
+	return MetadataHeroLevel
 }
 
-var BitVerseCollections = map[string]BitVerseCollection{
-	"hero": {
-		Singular: "Hero",
-		Name:     "BitVerse Heroes",
-		Address:  "0x6465ef3009f3c474774f4afb607a5d600ea71d95",
+// RarityValues returns the rarities of the collection from most to least
+// common.
+func (c BitVerseCollection) RarityValues() []string {
+	if len(c.Rarities) > 0 {
+		return c.Rarities
+	}
+
+	return Rarities
+}
+
+func (c BitVerseCollection) IsRarity(str string) bool {
+	for _, r := range c.RarityValues() {
+		if r == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c BitVerseCollection) GamePrefix() string {
+	if c.Metadata.GamePrefix != "" {
+		return c.Metadata.GamePrefix
+	}
+
+	return MetadataGamePrefix
+}
+
+func (c BitVerseCollection) NonTraitKeys() []string {
+	if len(c.Metadata.NonTraits) > 0 {
+		return c.Metadata.NonTraits
+	}
+
+	return MetadataNonTraits
+}
+
+// IsTrait reports whether the metadata key is a fixed trait of the token, as
+// opposed to its name, image, hero name, level or other game traits.
+func (c BitVerseCollection) IsTrait(key string) bool {
+	if key == c.HeroNameKey() || key == c.LevelKey() || strings.HasPrefix(key, c.GamePrefix()) {
+		return false
+	}
+
+	for _, k := range c.NonTraitKeys() {
+		if k == key {
+			return false
+		}
+	}
+
+	return true
+}
+
+type CollectionsFile struct {
+	Collections []BitVerseCollection `json:"collections"`
+}
+
+// DefaultCollections are used when no collections file exists.
+var DefaultCollections = []BitVerseCollection{
+	{
+		Key:            CollectionHero,
+		Singular:       "Hero",
+		Name:           "BitVerse Heroes",
+		Address:        "0x6465ef3009f3c474774f4afb607a5d600ea71d95",
+		TokenTroveSlug: "BitverseHeroes",
 	},
-	"portal": {
-		Singular: "Portal",
-		Name:     "BitVerse Portals",
-		Address:  "0xe4ac52f4b4a721d1d0ad8c9c689df401c2db7291",
+	{
+		Key:            CollectionPortal,
+		Singular:       "Portal",
+		Name:           "BitVerse Portals",
+		Address:        "0xe4ac52f4b4a721d1d0ad8c9c689df401c2db7291",
+		TokenTroveNote: "Portals are grouped by rarity on TokenTrove not individually",
 	},
 }
+
+// BitVerseCollections is the registry of known collections by key, and
+// CollectionKeys their keys in display order. Both are replaced by
+// LoadCollections.
+var (
+	BitVerseCollections = make(map[string]BitVerseCollection)
+	CollectionKeys      []string
+)
+
+func init() {
+	if err := setCollections(DefaultCollections); err != nil {
+		panic(err)
+	}
+}
+
+// LoadCollections replaces the registry with the collections in the file,
+// keeping the defaults when the file does not exist.
+func LoadCollections(path string) error {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file CollectionsFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return fmt.Errorf("could not parse collections file %v: %w", path, err)
+	}
+
+	return setCollections(file.Collections)
+}
+
+func setCollections(cols []BitVerseCollection) error {
+	if len(cols) == 0 {
+		return errors.New("no collections defined")
+	}
+
+	registry := make(map[string]BitVerseCollection, len(cols))
+	keys := make([]string, 0, len(cols))
+	for i, col := range cols {
+		if col.Key == "" || col.Name == "" || col.Address == "" {
+			return fmt.Errorf("collection %d: key, name and address are required", i)
+		}
+
+		if _, ok := registry[col.Key]; ok {
+			return fmt.Errorf("collection %d: duplicate key %v", i, col.Key)
+		}
+
+		if col.Singular == "" {
+			col.Singular = col.Name
+		}
+		col.Address = strings.ToLower(col.Address)
+
+		registry[col.Key] = col
+		keys = append(keys, col.Key)
+	}
+
+	for _, key := range RequiredCollections {
+		if _, ok := registry[key]; !ok {
+			return fmt.Errorf("the %v collection is required", key)
+		}
+	}
+
+	BitVerseCollections = registry
+	CollectionKeys = keys
+	return nil
+}
+
+// Collections returns every collection in display order.
+func Collections() []BitVerseCollection {
+	cols := make([]BitVerseCollection, 0, len(CollectionKeys))
+	for _, key := range CollectionKeys {
+		cols = append(cols, BitVerseCollections[key])
+	}
+
+	return cols
+}
+
+// AllRarities returns the rarities of every collection without repeats, in
+// collection order.
+func AllRarities() []string {
+	seen := make(map[string]bool)
+	var rarities []string
+	for _, col := range Collections() {
+		for _, r := range col.RarityValues() {
+			if !seen[r] {
+				seen[r] = true
+				rarities = append(rarities, r)
+			}
+		}
+	}
+
+	return rarities
+}
+
+// CollectionByAddress finds a collection by its token address.
+func CollectionByAddress(address string) (BitVerseCollection, bool) {
+	for _, col := range BitVerseCollections {
+		if strings.EqualFold(col.Address, address) {
+			return col, true
+		}
+	}
+
+	return BitVerseCollection{}, false
+}
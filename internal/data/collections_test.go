@@ -0,0 +1,46 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRarities(t *testing.T) {
+	defaults := DefaultCollections
+	t.Cleanup(func() {
+		if err := setCollections(defaults); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	gems := BitVerseCollection{Key: "gems", Name: "Gems", Address: "0x3333", Rarities: []string{"Common", "Shiny"}}
+	if err := setCollections(append(append([]BitVerseCollection{}, defaults...), gems)); err != nil {
+		t.Fatal(err)
+	}
+
+	hero := BitVerseCollections[CollectionHero]
+	if !reflect.DeepEqual(hero.RarityValues(), Rarities) {
+		t.Errorf("hero rarities %v, want the defaults %v", hero.RarityValues(), Rarities)
+	}
+
+	tests := []struct {
+		col    BitVerseCollection
+		rarity string
+		want   bool
+	}{
+		{col: hero, rarity: "Mythic", want: true},
+		{col: hero, rarity: "Shiny", want: false},
+		{col: gems, rarity: "Shiny", want: true},
+		{col: gems, rarity: "Mythic", want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.col.IsRarity(tt.rarity); got != tt.want {
+			t.Errorf("%v IsRarity(%v) = %v, want %v", tt.col.Name, tt.rarity, got, tt.want)
+		}
+	}
+
+	want := append(append([]string{}, Rarities...), "Shiny")
+	if got := AllRarities(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllRarities() = %v, want %v", got, want)
+	}
+}
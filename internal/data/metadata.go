@@ -0,0 +1,19 @@
+package data
+
+const (
+	MetadataRarity    = "Rarity"
+	MetadataHeroName  = "BHQ - Hero Name"
+	MetadataHeroLevel = "BHQ - Level"
+
+	// MetadataGamePrefix marks metadata updated by BitVerse HQ as heroes are
+	// played, such as the level.
+	MetadataGamePrefix = "BHQ - "
+)
+
+// MetadataNonTraits are the Immutable X metadata fields that describe a token
+// rather than one of its traits.
+var MetadataNonTraits = []string{"name", "description", "image_url", "animation_url", "external_url"}
+
+// Rarities are the BitVerse rarities, used by collections that do not list
+// their own.
+var Rarities = []string{"Common", "Rare", "Epic", "Legendary", "Mythic"}
@@ -0,0 +1,164 @@
+package discord
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DMChannelPrefix starts the IDs of the DM channels made by Recorder, which
+// are the prefix followed by the user ID.
+const DMChannelPrefix = "dm-"
+
+type Response struct {
+	Interaction *discordgo.Interaction
+	Response    *discordgo.InteractionResponse
+}
+
+type Edit struct {
+	Interaction *discordgo.Interaction
+	Edit        *discordgo.WebhookEdit
+}
+
+type Message struct {
+	ChannelID string
+	Message   *discordgo.MessageSend
+}
+
+// Recorder is a Transport that keeps every call instead of talking to
+// Discord. Guilds and channels are looked up in the maps, falling back to
+// objects with only the ID set.
+type Recorder struct {
+	Responses []Response
+	Edits     []Edit
+	Messages  []Message
+	Guilds    map[string]*discordgo.Guild
+	Channels  map[string]*discordgo.Channel
+	// Err, when set, is returned by every call that can fail.
+	Err error
+	mu  sync.Mutex
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		Guilds:   make(map[string]*discordgo.Guild),
+		Channels: make(map[string]*discordgo.Channel),
+	}
+}
+
+func (r *Recorder) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Err != nil {
+		return r.Err
+	}
+
+	r.Responses = append(r.Responses, Response{Interaction: interaction, Response: resp})
+	return nil
+}
+
+func (r *Recorder) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	r.Edits = append(r.Edits, Edit{Interaction: interaction, Edit: newresp})
+
+	msg := &discordgo.Message{ChannelID: interaction.ChannelID}
+	if newresp.Content != nil {
+		msg.Content = *newresp.Content
+	}
+	if newresp.Embeds != nil {
+		msg.Embeds = *newresp.Embeds
+	}
+
+	return msg, nil
+}
+
+func (r *Recorder) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	r.Messages = append(r.Messages, Message{ChannelID: channelID, Message: data})
+	return &discordgo.Message{ChannelID: channelID, Content: data.Content, Embeds: data.Embeds}, nil
+}
+
+func (r *Recorder) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	return &discordgo.Channel{ID: DMChannelPrefix + recipientID, Type: discordgo.ChannelTypeDM}, nil
+}
+
+func (r *Recorder) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if g, ok := r.Guilds[guildID]; ok {
+		return g, nil
+	}
+
+	return &discordgo.Guild{ID: guildID}, nil
+}
+
+func (r *Recorder) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.Channels[channelID]; ok {
+		return c, nil
+	}
+
+	return &discordgo.Channel{ID: channelID}, nil
+}
+
+// LastEdit returns the final content of the most recent interaction response
+// edit, or nil when there was none.
+func (r *Recorder) LastEdit() *discordgo.WebhookEdit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.Edits) == 0 {
+		return nil
+	}
+
+	return r.Edits[len(r.Edits)-1].Edit
+}
+
+// MessagesTo returns the messages sent to the channel. Use DMChannelPrefix
+// plus the user ID for direct messages.
+func (r *Recorder) MessagesTo(channelID string) []*discordgo.MessageSend {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var msgs []*discordgo.MessageSend
+	for _, m := range r.Messages {
+		if m.ChannelID == channelID {
+			msgs = append(msgs, m.Message)
+		}
+	}
+
+	return msgs
+}
+
+// Reset forgets every recorded call.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Responses = nil
+	r.Edits = nil
+	r.Messages = nil
+}
@@ -0,0 +1,20 @@
+// Package discord abstracts the Discord REST calls made while handling
+// commands and sending notifications, so they can be recorded instead of sent.
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Transport is the subset of *discordgo.Session used outside of the gateway
+// connection and command registration.
+type Transport interface {
+	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error)
+	Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+}
+
+var _ Transport = (*discordgo.Session)(nil)
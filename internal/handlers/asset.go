@@ -8,16 +8,20 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/deadloct/bitverse-nft-bot/internal/api"
 	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
 	log "github.com/sirupsen/logrus"
 )
 
 type AssetMessageHandler struct {
 	clientsManager *api.ClientsManager
 	col            data.BitVerseCollection
+	index          *index.Index
 }
 
-func NewAssetMessageHandler(col data.BitVerseCollection, clientsManager *api.ClientsManager) *AssetMessageHandler {
-	return &AssetMessageHandler{clientsManager: clientsManager, col: col}
+// NewAssetMessageHandler creates a handler for the collection. idx may be nil
+// for collections without an index, in which case rarity scores are omitted.
+func NewAssetMessageHandler(col data.BitVerseCollection, clientsManager *api.ClientsManager, idx *index.Index) *AssetMessageHandler {
+	return &AssetMessageHandler{clientsManager: clientsManager, col: col, index: idx}
 }
 
 func (h *AssetMessageHandler) HandleCommand(tokenID string) *discordgo.InteractionResponseData {
@@ -51,10 +55,30 @@ func (h *AssetMessageHandler) HandleCommand(tokenID string) *discordgo.Interacti
 		{Name: "Collection", Value: collectionURL},
 	}
 
+	var score index.Score
+	var scored bool
+	if h.index != nil {
+		score, scored = h.index.Score(tokenID)
+	}
+
+	if scored {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Rarity Score",
+			Value: fmt.Sprintf("%.2f (Rank %v of %v)", score.Score, score.Rank, h.index.Size()),
+		})
+	}
+
 	for k, v := range asset.GetMetadata() {
+		value := fmt.Sprintf("%v", v)
+		if scored {
+			if freq := h.index.TraitFrequency(k, v); freq > 0 {
+				value = fmt.Sprintf("%s (%.1f%% have this)", value, freq*100)
+			}
+		}
+
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:  k,
-			Value: fmt.Sprintf("%v", v),
+			Value: value,
 		})
 	}
 
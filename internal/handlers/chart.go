@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/chart"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+const ChartFilename = "chart.png"
+
+var RarityColors = map[string]color.RGBA{
+	"Common":    {0x9c, 0xa3, 0xaf, 0xff},
+	"Rare":      {0x3b, 0x82, 0xf6, 0xff},
+	"Epic":      {0xa8, 0x55, 0xf7, 0xff},
+	"Legendary": {0xf5, 0x9e, 0x0b, 0xff},
+	"Mythic":    {0xef, 0x44, 0x44, 0xff},
+}
+
+type ChartHandler struct {
+	store history.Store
+}
+
+func NewChartHandler(store history.Store) *ChartHandler {
+	return &ChartHandler{store: store}
+}
+
+// HandleCommand charts the floor price of the rarity, or of every rarity when
+// empty, over the window. An empty fiat charts prices in ETH.
+func (h *ChartHandler) HandleCommand(
+	collection string,
+	rarity string,
+	fiat coinbase.FiatSymbol,
+	window history.Window,
+) *discordgo.InteractionResponseData {
+
+	col := data.BitVerseCollections[collection]
+	rarities := col.RarityValues()
+	if rarity != "" {
+		rarities = []string{rarity}
+	}
+
+	c := chart.Chart{
+		Title: fmt.Sprintf("%s Floor %s", col.Name, window.Name),
+		Unit:  string(fiat),
+	}
+	if fiat == "" {
+		c.Unit = string(coinbase.CryptoETH)
+	}
+
+	since := time.Now().Add(-window.Duration)
+	for _, r := range rarities {
+		s := chart.Series{Name: r, Color: RarityColors[r]}
+		for _, sample := range h.store.Query(collection, r, since) {
+			s.Points = append(s.Points, chart.Point{At: sample.At, Value: sample.Value(fiat)})
+		}
+
+		if len(s.Points) > 0 {
+			c.Series = append(c.Series, s)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, c); err != nil {
+		if errors.Is(err, chart.ErrNoData) {
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("No %s price history recorded in the last %s", col.Name, window.Name)}
+		}
+
+		log.Errorf("could not render chart: %v", err)
+		return &discordgo.InteractionResponseData{Content: "Unable to render the chart"}
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("%s Floor Price (%s)", col.Name, window.Name),
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:     c.Title,
+				Image:     &discordgo.MessageEmbedImage{URL: "attachment://" + ChartFilename},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+		Files: []*discordgo.File{
+			{Name: ChartFilename, ContentType: "image/png", Reader: &buf},
+		},
+	}
+}
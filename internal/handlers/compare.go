@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxCompareAssets keeps the comparison to two rows of three inline fields.
+const MaxCompareAssets = 6
+
+type CompareHandler struct {
+	cm     *api.ClientsManager
+	col    data.BitVerseCollection
+	orders *OrdersHandler
+}
+
+func NewCompareHandler(col data.BitVerseCollection, cm *api.ClientsManager, orders *OrdersHandler) *CompareHandler {
+	return &CompareHandler{cm: cm, col: col, orders: orders}
+}
+
+// HandleCommand shows the assets side by side, one inline field each. Every
+// field lists the same traits in the same order so the rows line up.
+func (h *CompareHandler) HandleCommand(tokenIDs []string, currency coinbase.FiatSymbol) *discordgo.InteractionResponseData {
+	tokenIDs = uniqueStrings(tokenIDs)
+	if len(tokenIDs) < 2 {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Give at least two different %s IDs to compare", h.col.Singular)}
+	}
+
+	if len(tokenIDs) > MaxCompareAssets {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Compare at most %v %s", MaxCompareAssets, h.col.Name)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var assets []*imxapi.Asset
+	for _, tokenID := range tokenIDs {
+		asset, err := h.cm.AssetsClient.GetAsset(ctx, h.col.Address, tokenID, true)
+		if err != nil {
+			log.Error(err)
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Error retrieving %s for token ID %s", h.col.Singular, tokenID)}
+		}
+
+		if asset == nil || asset.TokenId != tokenID {
+			return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Could not find a %s with token ID %s", h.col.Singular, tokenID)}
+		}
+
+		assets = append(assets, asset)
+	}
+
+	traits := compareTraits(h.col, assets)
+	title := fmt.Sprintf("%s Comparison", h.col.Singular)
+
+	// Discord limits both each field and the whole embed, so heroes with many
+	// traits show as many as fit and always keep the owner and listing.
+	var fields []*discordgo.MessageEmbedField
+	var values [][]string
+	var tails [][]string
+	budget := MaxEmbedTotalLength - len(title)
+	for _, asset := range assets {
+		metadata := asset.GetMetadata()
+
+		var lines []string
+		for _, trait := range traits {
+			value := "—"
+			if v, ok := metadata[trait]; ok {
+				value = fmt.Sprintf("%v", v)
+			}
+			lines = append(lines, fmt.Sprintf("**%s**: %s", trait, value))
+		}
+
+		owner := asset.GetUser()
+		tail := []string{fmt.Sprintf("**Owner**: [%s](%s)", shortAddress(owner), GetImmutascanUserURL(owner))}
+
+		listing := "Not listed"
+		order, err := h.orders.GetListing(ctx, h.col.Address, asset.TokenId)
+		if err != nil {
+			log.Errorf("unable to retrieve listing for %v: %v", asset.TokenId, err)
+			listing = "Unknown"
+		} else if order != nil {
+			listing = h.orders.FormatOrderPrice(*order, currency)
+		}
+		tail = append(tail, fmt.Sprintf("**Listed**: %s", listing))
+
+		name := asset.GetName()
+		if name == "" {
+			name = h.col.Singular
+		}
+
+		field := &discordgo.MessageEmbedField{
+			Name:   truncate(fmt.Sprintf("%s #%s", name, asset.TokenId), MaxEmbedFieldNameLength),
+			Inline: true,
+		}
+		budget -= len(field.Name)
+
+		fields = append(fields, field)
+		values = append(values, lines)
+		tails = append(tails, tail)
+	}
+
+	budget /= len(fields)
+	if budget > MaxEmbedFieldLength {
+		budget = MaxEmbedFieldLength
+	}
+	for i, field := range fields {
+		field.Value = fitLines(values[i], tails[i], budget)
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("Comparing %v %s", len(assets), h.col.Name),
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:     title,
+				Fields:    fields,
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+// fitLines joins as many lines as fit in max bytes followed by the tail,
+// noting how many lines were left out.
+func fitLines(lines, tail []string, max int) string {
+	tailStr := strings.Join(tail, "\n")
+
+	var kept []string
+	length := len(tailStr)
+	for i, line := range lines {
+		more := fmt.Sprintf("…and %v more", len(lines)-i)
+		if length+len(line)+1 > max || (i < len(lines)-1 && length+len(line)+len(more)+2 > max) {
+			kept = append(kept, more)
+			break
+		}
+
+		kept = append(kept, line)
+		length += len(line) + 1
+	}
+
+	return truncate(strings.Join(append(kept, tailStr), "\n"), max)
+}
+
+// compareTraits returns every metadata key of the assets, with rarity, hero
+// name and level first and the rest alphabetical.
+func compareTraits(col data.BitVerseCollection, assets []*imxapi.Asset) []string {
+	first := []string{col.RarityKey(), col.HeroNameKey(), col.LevelKey()}
+
+	keys := make(map[string]bool)
+	for _, asset := range assets {
+		for k := range asset.GetMetadata() {
+			keys[k] = true
+		}
+	}
+
+	var traits []string
+	for _, k := range first {
+		if keys[k] {
+			traits = append(traits, k)
+			delete(keys, k)
+		}
+	}
+
+	var rest []string
+	for k := range keys {
+		rest = append(rest, k)
+	}
+	sort.Strings(rest)
+
+	return append(traits, rest...)
+}
+
+// uniqueStrings returns the values without repeats, keeping the first of each
+// in order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func shortAddress(address string) string {
+	if len(address) < 10 {
+		return address
+	}
+
+	return address[:6] + "…" + address[len(address)-4:]
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+func TestCompareRemovesDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokenIDs []string
+		fields   int
+		content  string
+	}{
+		{name: "different heroes", tokenIDs: []string{"1", "2", "3"}, fields: 3, content: "Comparing 3"},
+		{name: "repeated hero", tokenIDs: []string{"2", "1", "2"}, fields: 2, content: "Comparing 2"},
+		{name: "one hero twice", tokenIDs: []string{"1", "1"}, content: "at least two different"},
+		{name: "repeats over the limit", tokenIDs: []string{"1", "1", "1", "1", "1", "1", "2"}, fields: 2, content: "Comparing 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := newTestOrdersHandler(3)
+			h := NewCompareHandler(data.BitVerseCollections[data.CollectionHero], orders.cm, orders)
+
+			response := h.HandleCommand(tt.tokenIDs, coinbase.FiatUSD)
+			if !strings.Contains(response.Content, tt.content) {
+				t.Errorf("got %q, want %q", response.Content, tt.content)
+			}
+
+			var fields int
+			if len(response.Embeds) > 0 {
+				fields = len(response.Embeds[0].Fields)
+			}
+			if fields != tt.fields {
+				t.Errorf("got %v fields, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord rejects messages over these limits.
+const (
+	MaxEmbeds               = 10
+	MaxEmbedTotalLength     = 6000
+	MaxEmbedFieldLength     = 1024
+	MaxEmbedFieldNameLength = 256
+)
+
+// EmbedLength counts the characters of an embed that Discord counts towards
+// MaxEmbedTotalLength.
+func EmbedLength(e *discordgo.MessageEmbed) int {
+	n := len(e.Title) + len(e.Description)
+	if e.Footer != nil {
+		n += len(e.Footer.Text)
+	}
+	if e.Author != nil {
+		n += len(e.Author.Name)
+	}
+	for _, f := range e.Fields {
+		n += len(f.Name) + len(f.Value)
+	}
+
+	return n
+}
+
+// truncate shortens s to at most max bytes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	const ellipsis = "…"
+	end := max - len(ellipsis)
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	return s[:end] + ellipsis
+}
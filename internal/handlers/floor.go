@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// Floor is the cheapest active listing of a single rarity. Order is nil when
+// nothing of that rarity is listed.
+type Floor struct {
+	Rarity       string
+	Order        *imxapi.Order
+	CryptoPrice  float64
+	CryptoSymbol coinbase.CryptoSymbol
+}
+
+// GetFloors returns the floor of every rarity in the collection, in the order
+// of its rarities.
+func (h *OrdersHandler) GetFloors(ctx context.Context, col data.BitVerseCollection, buyTokenType string) ([]Floor, error) {
+	var floors []Floor
+	for _, rarity := range col.RarityValues() {
+		metadata, err := json.Marshal(map[string][]string{col.RarityKey(): {rarity}})
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := h.cm.OrdersClient.ListOrders(ctx, &orders.ListOrdersConfig{
+			BuyTokenType:     buyTokenType,
+			PageSize:         1,
+			SellTokenAddress: col.Address,
+			Status:           "active",
+			OrderBy:          "buy_quantity_with_fees",
+			Direction:        "asc",
+			SellMetadata:     string(metadata),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		floor := Floor{Rarity: rarity}
+		if len(result) > 0 {
+			floor.Order = &result[0]
+			floor.CryptoPrice = h.getPrice(result[0])
+			floor.CryptoSymbol = h.getCryptoSymbol(result[0].GetBuy().Type)
+		}
+
+		floors = append(floors, floor)
+	}
+
+	return floors, nil
+}
+
+func (h *OrdersHandler) HandleFloorCommand(
+	col data.BitVerseCollection,
+	buyTokenType string,
+	currency coinbase.FiatSymbol,
+) *discordgo.InteractionResponseData {
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	floors, err := h.GetFloors(ctx, col, buyTokenType)
+	if err != nil {
+		log.Error(err)
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to fetch %s floor prices", col.Name)}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, floor := range floors {
+		value := "No active listings"
+		if floor.Order != nil {
+			tokenID := floor.Order.Sell.Data.GetTokenId()
+			fiatPrice := floor.CryptoPrice * h.spot.RetrieveSpotPrice(floor.CryptoSymbol, currency)
+			value = fmt.Sprintf(
+				"%f %s / %s\n%s %s\n%s",
+				floor.CryptoPrice,
+				floor.CryptoSymbol,
+				h.FormatPrice(fiatPrice, currency),
+				col.Singular,
+				tokenID,
+				GetOrderURLs(col.Address, tokenID).Immutascan,
+			)
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{Name: floor.Rarity, Value: value})
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("%s Floor Prices", col.Name),
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:     fmt.Sprintf("%s Floor Prices (Confirm Fees on Web)", col.Name),
+				URL:       GetImmutascanUserURL(col.Address),
+				Fields:    fields,
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
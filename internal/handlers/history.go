@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+type HistoryHandler struct {
+	store history.Store
+}
+
+func NewHistoryHandler(store history.Store) *HistoryHandler {
+	return &HistoryHandler{store: store}
+}
+
+// HandleCommand reports floor price stats for each window. An empty fiat
+// reports prices in ETH, an empty rarity reports every rarity.
+func (h *HistoryHandler) HandleCommand(collection, rarity string, fiat coinbase.FiatSymbol) *discordgo.InteractionResponseData {
+	col := data.BitVerseCollections[collection]
+
+	rarities := col.RarityValues()
+	if rarity != "" {
+		rarities = []string{rarity}
+	}
+
+	now := time.Now()
+	oldest := now.Add(-history.Windows[len(history.Windows)-1].Duration)
+
+	var fields []*discordgo.MessageEmbedField
+	for _, r := range rarities {
+		samples := h.store.Query(collection, r, oldest)
+
+		var lines []string
+		for _, window := range history.Windows {
+			stats := history.Compute(since(samples, now.Add(-window.Duration)), fiat)
+			if stats.Count == 0 {
+				lines = append(lines, fmt.Sprintf("%s: no data", window.Name))
+				continue
+			}
+
+			lines = append(lines, fmt.Sprintf(
+				"%s: min %s, max %s, avg %s, change %s (%+0.1f%%)",
+				window.Name,
+				h.format(stats.Min, fiat),
+				h.format(stats.Max, fiat),
+				h.format(stats.Avg, fiat),
+				h.format(stats.Change, fiat),
+				stats.ChangePct,
+			))
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{Name: r, Value: strings.Join(lines, "\n")})
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("%s Floor Price History", col.Name),
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:     fmt.Sprintf("%s Floor Price History", col.Name),
+				Fields:    fields,
+				Timestamp: now.Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+func (h *HistoryHandler) format(v float64, fiat coinbase.FiatSymbol) string {
+	if fiat == "" {
+		return fmt.Sprintf("%0.4f ETH", v)
+	}
+
+	return FormatPrice(v, fiat)
+}
+
+func since(samples []history.Sample, t time.Time) []history.Sample {
+	for i, sample := range samples {
+		if !sample.At.Before(t) {
+			return samples[i:]
+		}
+	}
+
+	return nil
+}
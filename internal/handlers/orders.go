@@ -4,40 +4,51 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
 	"github.com/deadloct/immutablex-go-lib/coinbase"
 	"github.com/deadloct/immutablex-go-lib/orders"
-	"github.com/deadloct/immutablex-go-lib/utils"
 	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	MaxContentLength    = 1900
-	MetadataHeroName    = "BHQ - Hero Name"
-	MetadataHeroLevel   = "BHQ - Level"
+	MaxOrderResults     = 200
+	MetadataHeroName    = data.MetadataHeroName
+	MetadataHeroLevel   = data.MetadataHeroLevel
 	ImmutableUSDCSymbol = "ERC20"
 
 	TokenTypeETH   = "ETH"
 	TokenTypeERC20 = "ERC20"
+
+	// OrderByRarityRank sorts orders by the rarity rank of their token, rarest
+	// first when ascending. Immutable X cannot sort by it, so the cheapest
+	// MaxOrderResults orders are fetched and sorted locally.
+	OrderByRarityRank = "rarity_rank"
 )
 
 type Metadata map[string]interface{}
 
 type OrdersHandler struct {
-	cm       *api.ClientsManager
-	coinbase *coinbase.CoinbaseClient
+	cm    *api.ClientsManager
+	index *index.Index
+	spot  api.SpotPriceClient
 }
 
-func NewOrdersHandler(cm *api.ClientsManager) *OrdersHandler {
+// NewOrdersHandler creates an orders handler. idx is the hero index used for
+// rarity ranks and may be nil when they are not needed.
+func NewOrdersHandler(cm *api.ClientsManager, idx *index.Index) *OrdersHandler {
 	return &OrdersHandler{
-		cm:       cm,
-		coinbase: coinbase.GetCoinbaseClientInstance(),
+		cm:    cm,
+		index: idx,
+		spot:  cm.SpotPriceClient,
 	}
 }
 
@@ -46,86 +57,231 @@ func (h *OrdersHandler) HandleCommand(
 	format string,
 	currency coinbase.FiatSymbol,
 ) *discordgo.InteractionResponseData {
+	response, _ := h.HandlePage(cfg, format, currency, PageCursor{})
+	return response
+}
+
+// PageCursor is where a page of results starts: the API cursor of the page of
+// orders it is in, how many of those orders earlier pages already showed, and
+// how many results came before it in total.
+type PageCursor struct {
+	Cursor string
+	Skip   int
+	Offset int
+}
+
+// HandlePage fetches one page of up to cfg.PageSize orders starting at start,
+// and shows as many of them as fit in a single message. It returns where the
+// next page starts, or nil on the last page. Orders sorted by rarity rank are
+// sorted locally, so only the cheapest MaxOrderResults can be browsed.
+func (h *OrdersHandler) HandlePage(
+	cfg *orders.ListOrdersConfig,
+	format string,
+	currency coinbase.FiatSymbol,
+	start PageCursor,
+) (*discordgo.InteractionResponseData, *PageCursor) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 
-	result, err := h.cm.OrdersClient.ListOrders(ctx, cfg)
+	page, err := h.listPage(ctx, cfg, start.Cursor)
 	if err != nil {
 		log.Error(err)
-		return &discordgo.InteractionResponseData{Content: "Unable to fetch orders for the provided query"}
+		return &discordgo.InteractionResponseData{Content: "Unable to fetch orders for the provided query"}, nil
+	}
+
+	var result []imxapi.Order
+	if start.Skip < len(page.Result) {
+		result = page.Result[start.Skip:]
+	}
+	if cfg.PageSize > 0 && len(result) > cfg.PageSize {
+		result = result[:cfg.PageSize]
 	}
 
 	if len(result) == 0 {
-		return &discordgo.InteractionResponseData{Content: "No results found"}
+		return &discordgo.InteractionResponseData{Content: "No results found"}, nil
 	}
 
+	// Assets are fetched one at a time, stopping once the message is full.
 	metadata := make(map[string]Metadata, len(result))
-	for _, r := range result {
-		data := r.Sell.GetData()
+	addMetadata := func(order imxapi.Order) {
+		data := order.Sell.GetData()
 		asset, err := h.cm.AssetsClient.GetAsset(ctx, data.GetTokenAddress(), data.GetTokenId(), false)
 		if err != nil {
 			log.Errorf("unable to retrieve asset %v: %v", data.GetTokenId(), err)
-			continue
+			return
 		}
 
 		metadata[data.GetTokenId()] = asset.GetMetadata()
 	}
 
+	// Room for the longest header, "Results 123456-123456:".
+	const headerLength = 32
+
+	var response *discordgo.InteractionResponseData
+	var shown int
 	switch format {
 	case "summary":
-		var summaries []string
-		for _, order := range result {
-			summaries = append(summaries, h.getSummaryForOrder(order, currency, metadata))
-		}
-
-		first := fmt.Sprintf("%v results:", len(result))
-		summaries = append([]string{first}, summaries...)
-
 		var content string
-		for i, summary := range summaries {
-			if len(summary)+len(content) > MaxContentLength {
-				content += "\n... (Max Discord length reached)"
+		for _, order := range result {
+			addMetadata(order)
+			summary := h.getSummaryForOrder(order, currency, metadata)
+			if shown > 0 && headerLength+len(content)+len(summary)+2 > MaxContentLength {
 				break
 			}
 
-			if i == 0 {
-				content += summary
-			} else {
-				content += "\n\n" + summary
-			}
+			content += "\n\n" + truncate(summary, MaxContentLength-headerLength-2)
+			shown++
 		}
 
-		return &discordgo.InteractionResponseData{Content: content}
+		response = &discordgo.InteractionResponseData{Content: content}
 
 	default:
 		var embeds []*discordgo.MessageEmbed
+		var length int
 		for _, order := range result {
-			embeds = append(embeds, h.getEmbedForOrder(order, currency, metadata))
-		}
+			addMetadata(order)
+			embed := h.getEmbedForOrder(order, currency, metadata)
+			if shown > 0 && (shown == MaxEmbeds || length+EmbedLength(embed) > MaxEmbedTotalLength) {
+				break
+			}
 
-		return &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("%v Results", len(result)),
-			Embeds:  embeds,
+			embeds = append(embeds, embed)
+			length += EmbedLength(embed)
+			shown++
 		}
+
+		response = &discordgo.InteractionResponseData{Embeds: embeds}
+	}
+
+	// Continue in the same page of orders when not all of it fit, otherwise
+	// at the next page.
+	var next *PageCursor
+	switch {
+	case start.Skip+shown < len(page.Result):
+		next = &PageCursor{Cursor: start.Cursor, Skip: start.Skip + shown, Offset: start.Offset + shown}
+	case page.Remaining > 0 && page.Cursor != "":
+		next = &PageCursor{Cursor: page.Cursor, Offset: start.Offset + shown}
+	}
+
+	header := fmt.Sprintf("%v results", shown)
+	if start.Offset > 0 || next != nil {
+		header = fmt.Sprintf("Results %v-%v", start.Offset+1, start.Offset+shown)
 	}
+
+	if format == "summary" {
+		response.Content = header + ":" + response.Content
+	} else {
+		response.Content = header
+	}
+
+	return response, next
 }
 
-func (h *OrdersHandler) FormatPrice(price float64, fiat coinbase.FiatSymbol) string {
-	var symbol string
+// listPage fetches the page of orders at the cursor. Immutable X cannot sort
+// by rarity rank, so that sort fetches the cheapest MaxOrderResults orders as
+// a single page and sorts them.
+func (h *OrdersHandler) listPage(ctx context.Context, cfg *orders.ListOrdersConfig, cursor string) (*imxapi.ListOrdersResponse, error) {
+	query := *cfg
+	if cfg.OrderBy != OrderByRarityRank {
+		query.Cursor = cursor
+		return h.cm.OrdersPager.ListOrdersPage(ctx, &query)
+	}
 
-	switch fiat {
-	case coinbase.FiatEUR:
-		symbol = "€"
-	case coinbase.FiatGBP:
-		symbol = "£"
-	default:
-		symbol = "$"
+	query.PageSize = MaxOrderResults
+	query.OrderBy = "buy_quantity_with_fees"
+	query.Direction = "asc"
+	result, err := h.cm.OrdersClient.ListOrders(ctx, &query)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Debugf("asked to format price %0.2f in currency %v", price, fiat)
+	h.sortByRarityRank(result, cfg.Direction == "desc")
+	return &imxapi.ListOrdersResponse{Result: result}, nil
+}
+
+// GetListing returns the cheapest active listing of a token, or nil when it is
+// not listed.
+func (h *OrdersHandler) GetListing(ctx context.Context, tokenAddress, tokenID string) (*imxapi.Order, error) {
+	result, err := h.cm.OrdersClient.ListOrders(ctx, &orders.ListOrdersConfig{
+		PageSize:         1,
+		SellTokenAddress: tokenAddress,
+		SellTokenID:      tokenID,
+		Status:           "active",
+		OrderBy:          "buy_quantity_with_fees",
+		Direction:        "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	return &result[0], nil
+}
+
+// GetListings returns the cheapest active listing of each token that has
+// one. The cheapest MaxOrderResults listings of the collection are fetched at
+// once, and only tokens missing from a full page are looked up one by one.
+func (h *OrdersHandler) GetListings(ctx context.Context, tokenAddress string, tokenIDs []string) (map[string]imxapi.Order, error) {
+	result, err := h.cm.OrdersClient.ListOrders(ctx, &orders.ListOrdersConfig{
+		PageSize:         MaxOrderResults,
+		SellTokenAddress: tokenAddress,
+		Status:           "active",
+		OrderBy:          "buy_quantity_with_fees",
+		Direction:        "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(tokenIDs))
+	for _, id := range tokenIDs {
+		wanted[id] = true
+	}
+
+	listings := make(map[string]imxapi.Order)
+	for _, order := range result {
+		id := order.Sell.Data.GetTokenId()
+		if _, ok := listings[id]; wanted[id] && !ok {
+			listings[id] = order
+		}
+	}
+
+	if len(result) < MaxOrderResults {
+		return listings, nil
+	}
+
+	for _, id := range tokenIDs {
+		if _, ok := listings[id]; ok {
+			continue
+		}
+
+		order, err := h.GetListing(ctx, tokenAddress, id)
+		if err != nil {
+			return nil, err
+		}
+		if order != nil {
+			listings[id] = *order
+		}
+	}
+
+	return listings, nil
+}
+
+// FormatOrderPrice formats the price of an order in crypto and fiat.
+func (h *OrdersHandler) FormatOrderPrice(order imxapi.Order, fiat coinbase.FiatSymbol) string {
+	cryptoPrice := h.getPrice(order)
+	cryptoSymbol := h.getCryptoSymbol(order.GetBuy().Type)
+	fiatPrice := cryptoPrice * h.spot.RetrieveSpotPrice(cryptoSymbol, fiat)
+	return fmt.Sprintf("%f %s / %s", cryptoPrice, cryptoSymbol, h.FormatPrice(fiatPrice, fiat))
+}
 
-	return fmt.Sprintf("%s%0.2f", symbol, price)
+func (h *OrdersHandler) FormatPrice(price float64, fiat coinbase.FiatSymbol) string {
+	log.Debugf("asked to format price %0.2f in currency %v", price, fiat)
+	return FormatPrice(price, fiat)
 }
 
 func (h *OrdersHandler) getSummaryForOrder(order imxapi.Order, fiatType coinbase.FiatSymbol, metadata map[string]Metadata) string {
@@ -138,12 +294,14 @@ func (h *OrdersHandler) getSummaryForOrder(order imxapi.Order, fiatType coinbase
 	}
 
 	urls := GetOrderURLs(collection, tokenID)
-	cryptoPrice := h.getPrice(order)
-	cryptoSymbol := h.getCryptoSymbol(order.GetBuy().Type)
-	fiatPrice := cryptoPrice * h.coinbase.RetrieveSpotPrice(cryptoSymbol, fiatType)
-	priceStr := fmt.Sprintf("%f %s / %s", cryptoPrice, cryptoSymbol, h.FormatPrice(fiatPrice, fiatType))
+	priceStr := h.FormatOrderPrice(order, fiatType)
 
-	return fmt.Sprintf("• __%s__ (%s -- Confirm Fees on Web)\n  Hero Name: %s\n  Link: <%s>", name, priceStr, h.getHeroName(tokenID, metadata), urls.Immutascan)
+	summary := fmt.Sprintf("• __%s__ (%s -- Confirm Fees on Web)\n  Hero Name: %s", name, priceStr, h.getHeroName(collection, tokenID, metadata))
+	if rank := h.getRarityRank(collection, tokenID); rank != "" {
+		summary += "\n  Rarity Rank: " + rank
+	}
+
+	return fmt.Sprintf("%s\n  Link: <%s>", summary, urls.Immutascan)
 }
 
 func (h *OrdersHandler) getEmbedForOrder(order imxapi.Order, fiatType coinbase.FiatSymbol, metadata map[string]Metadata) *discordgo.MessageEmbed {
@@ -156,19 +314,15 @@ func (h *OrdersHandler) getEmbedForOrder(order imxapi.Order, fiatType coinbase.F
 		name = "Item " + tokenID
 	}
 	urls := GetOrderURLs(collection, tokenID)
-	orderID := order.OrderId
-	orderURL := strings.Join([]string{utils.ImmutascanURL, "order", fmt.Sprint(orderID)}, "/")
+	orderURL := GetImmutascanOrderURL(order.OrderId)
 
-	cryptoPrice := h.getPrice(order)
-	cryptoSymbol := h.getCryptoSymbol(order.GetBuy().Type)
-	fiatPrice := cryptoPrice * h.coinbase.RetrieveSpotPrice(cryptoSymbol, fiatType)
-	priceStr := fmt.Sprintf("%f %s / %s", cryptoPrice, cryptoSymbol, h.FormatPrice(fiatPrice, fiatType))
+	priceStr := h.FormatOrderPrice(order, fiatType)
 
 	imageURL := data.Properties.GetImageUrl()
 	title := fmt.Sprintf("%s (%s -- Confirm Fees on Web)", name, priceStr)
 
 	fields := []*discordgo.MessageEmbedField{
-		{Name: "Hero Name", Value: h.getHeroName(tokenID, metadata)},
+		{Name: "Hero Name", Value: h.getHeroName(collection, tokenID, metadata)},
 		{Name: "Stats", Value: order.Status},
 		{Name: "Owner", Value: GetImmutascanUserURL(user) + "?tab=1&forSale=true"},
 		{Name: "Immutable Market Listing", Value: urls.ImmutableMarket},
@@ -178,6 +332,10 @@ func (h *OrdersHandler) getEmbedForOrder(order imxapi.Order, fiatType coinbase.F
 		{Name: "Record of Listing", Value: orderURL},
 	}
 
+	if rank := h.getRarityRank(collection, tokenID); rank != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Rarity Rank", Value: rank})
+	}
+
 	return &discordgo.MessageEmbed{
 		Title:     title,
 		URL:       urls.Immutascan,
@@ -187,6 +345,48 @@ func (h *OrdersHandler) getEmbedForOrder(order imxapi.Order, fiatType coinbase.F
 	}
 }
 
+// getRarityRank describes the rarity rank of the token, or returns an empty
+// string when the token is not in the hero index.
+func (h *OrdersHandler) getRarityRank(tokenAddress, tokenID string) string {
+	if h.index == nil || tokenAddress != data.BitVerseCollections[data.CollectionHero].Address {
+		return ""
+	}
+
+	score, ok := h.index.Score(tokenID)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%v of %v (Score %.2f)", score.Rank, h.index.Size(), score.Score)
+}
+
+// sortByRarityRank sorts orders rarest first, or most common first when
+// reversed. Orders of tokens without a rank go last either way.
+func (h *OrdersHandler) sortByRarityRank(result []imxapi.Order, reverse bool) {
+	rank := func(order imxapi.Order) int {
+		sell := order.Sell.GetData()
+		if h.index == nil || sell.GetTokenAddress() != data.BitVerseCollections[data.CollectionHero].Address {
+			return 0
+		}
+
+		score, _ := h.index.Score(sell.GetTokenId())
+		return score.Rank
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := rank(result[i]), rank(result[j])
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+
+		if reverse {
+			return a > b
+		}
+
+		return a < b
+	})
+}
+
 func (h *OrdersHandler) getPrice(order imxapi.Order) float64 {
 	// Deprecated field, but updates not yet available in imx's go lib.
 	price := order.GetBuy().Data.QuantityWithFees
@@ -201,10 +401,15 @@ func (h *OrdersHandler) getPrice(order imxapi.Order) float64 {
 	return float64(amount) * math.Pow10(-1*decimals)
 }
 
-func (h *OrdersHandler) getHeroName(tokenID string, metaMap map[string]Metadata) string {
+func (h *OrdersHandler) getHeroName(tokenAddress, tokenID string, metaMap map[string]Metadata) string {
+	key := MetadataHeroName
+	if col, ok := data.CollectionByAddress(tokenAddress); ok {
+		key = col.HeroNameKey()
+	}
+
 	heroName := "(Unknown)"
 	if m, ok := metaMap[tokenID]; ok {
-		if n, ok := m[MetadataHeroName]; ok {
+		if n, ok := m[key]; ok {
 			if name, ok := n.(string); ok && name != "" {
 				heroName = name
 			}
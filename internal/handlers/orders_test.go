@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+var itemPattern = regexp.MustCompile(`Item #(\d+)`)
+
+// newTestOrdersHandler lists n heroes, token IDs 1 to n, getting cheaper as
+// the IDs go up.
+func newTestOrdersHandler(n int) *OrdersHandler {
+	address := data.BitVerseCollections[data.CollectionHero].Address
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	assets := fake.NewAssets()
+	list := fake.NewOrders(assets)
+	for i := 1; i <= n; i++ {
+		tokenID := fmt.Sprint(i)
+		assets.Put(fake.Asset(address, tokenID, "0x2222222222222222222222222222222222222222", map[string]interface{}{
+			data.MetadataRarity:   "Common",
+			data.MetadataHeroName: "Hero " + tokenID,
+		}))
+		list.Put(fake.Order(int32(i), address, tokenID, float64(n-i+1)/100, start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	spot := fake.NewSpotPrices()
+	spot.Set(coinbase.CryptoETH, coinbase.FiatUSD, 2000)
+
+	return NewOrdersHandler(fake.NewClientsManager(list, assets, spot), nil)
+}
+
+func TestHandlePageShowsEveryOrderOnce(t *testing.T) {
+	tests := []struct {
+		name     string
+		orders   int
+		pageSize int
+		format   string
+		orderBy  string
+	}{
+		{name: "summary fits in one page", orders: 3, pageSize: 5, format: "summary"},
+		{name: "summary longer than a message", orders: 40, pageSize: 25, format: "summary"},
+		{name: "summary over several API pages", orders: 12, pageSize: 5, format: "summary"},
+		{name: "embeds", orders: 12, pageSize: 5, format: "detailed"},
+		// Without an index every rank ties, leaving the orders cheapest first.
+		{name: "sorted locally by rarity rank", orders: 12, pageSize: 5, format: "summary", orderBy: OrderByRarityRank},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestOrdersHandler(tt.orders)
+			cfg := &orders.ListOrdersConfig{
+				PageSize:  tt.pageSize,
+				Status:    "active",
+				OrderBy:   "buy_quantity_with_fees",
+				Direction: "asc",
+			}
+			if tt.orderBy != "" {
+				cfg.OrderBy = tt.orderBy
+			}
+
+			seen := make(map[string]int)
+			var shown []string
+			start := PageCursor{}
+			for page := 0; ; page++ {
+				if page > tt.orders {
+					t.Fatalf("more pages than orders, stuck at %#v", start)
+				}
+
+				response, next := h.HandlePage(cfg, tt.format, coinbase.FiatUSD, start)
+
+				text := response.Content
+				if tt.format == "summary" && len(text) > MaxContentLength {
+					t.Errorf("page %v content is %v long, over %v", page, len(text), MaxContentLength)
+				}
+
+				if len(response.Embeds) > MaxEmbeds {
+					t.Errorf("page %v has %v embeds, over %v", page, len(response.Embeds), MaxEmbeds)
+				}
+
+				var length int
+				for _, embed := range response.Embeds {
+					length += EmbedLength(embed)
+					text += "\n" + embed.Title
+				}
+				if length > MaxEmbedTotalLength {
+					t.Errorf("page %v embeds are %v long, over %v", page, length, MaxEmbedTotalLength)
+				}
+
+				for _, m := range itemPattern.FindAllStringSubmatch(text, -1) {
+					seen[m[1]]++
+					shown = append(shown, m[1])
+				}
+
+				if next == nil {
+					break
+				}
+				start = *next
+			}
+
+			if len(seen) != tt.orders {
+				t.Errorf("showed %v distinct orders, want %v", len(seen), tt.orders)
+			}
+			for id, count := range seen {
+				if count != 1 {
+					t.Errorf("order of token %v shown %v times, want once", id, count)
+				}
+			}
+
+			// Cheapest first, which is the highest token ID first.
+			for i, id := range shown {
+				if want := fmt.Sprint(tt.orders - i); id != want {
+					t.Errorf("result %v is token %v, want %v", i, id, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestHandlePageNoResults(t *testing.T) {
+	h := newTestOrdersHandler(0)
+
+	response, next := h.HandlePage(&orders.ListOrdersConfig{PageSize: 5}, "summary", coinbase.FiatUSD, PageCursor{})
+	if next != nil {
+		t.Errorf("next = %#v, want nil", next)
+	}
+
+	if response.Content != "No results found" {
+		t.Errorf("content = %q, want %q", response.Content, "No results found")
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+// GroupValue is the estimated value of the NFTs of one collection and rarity
+// in a wallet.
+type GroupValue struct {
+	Collection string
+	Rarity     string
+	Count      int
+	Unvalued   int
+	FloorETH   float64
+	ValueETH   float64
+}
+
+// Valuation estimates a wallet from current floor prices. Items with no floor
+// and no usable listing are counted in Unvalued and left out of the totals.
+type Valuation struct {
+	Groups   []GroupValue
+	Count    int
+	Unvalued int
+	ValueETH float64
+}
+
+// Value estimates every item from the ETH floor of its collection and rarity.
+// With useListings, listed items are valued at their own listing price
+// instead, converted to ETH through the spot price when listed in another
+// token.
+func (h *WalletHandler) Value(ctx context.Context, items []WalletItem, useListings bool, currency coinbase.FiatSymbol) (*Valuation, error) {
+	floors := make(map[string]map[string]float64)
+	for _, item := range items {
+		address := item.Collection.Address
+		if _, ok := floors[address]; ok {
+			continue
+		}
+
+		colFloors, err := h.orders.GetFloors(ctx, item.Collection, TokenTypeETH)
+		if err != nil {
+			return nil, err
+		}
+
+		floors[address] = make(map[string]float64)
+		for _, floor := range colFloors {
+			if floor.Order != nil {
+				floors[address][floor.Rarity] = floor.CryptoPrice
+			}
+		}
+	}
+
+	ethSpot := h.orders.spot.RetrieveSpotPrice(coinbase.CryptoETH, currency)
+
+	valuation := &Valuation{}
+	for _, group := range groupWalletItems(items) {
+		first := group[0]
+		gv := GroupValue{
+			Collection: first.Collection.Name,
+			Rarity:     first.Rarity,
+			Count:      len(group),
+			FloorETH:   floors[first.Collection.Address][first.Rarity],
+		}
+
+		for _, item := range group {
+			value := gv.FloorETH
+			if useListings && item.Order != nil {
+				value = h.listingETH(item, currency, ethSpot)
+			}
+
+			if value == 0 {
+				gv.Unvalued++
+				continue
+			}
+
+			gv.ValueETH += value
+		}
+
+		valuation.Groups = append(valuation.Groups, gv)
+		valuation.Count += gv.Count
+		valuation.Unvalued += gv.Unvalued
+		valuation.ValueETH += gv.ValueETH
+	}
+
+	return valuation, nil
+}
+
+// listingETH converts the listing price of the item to ETH, returning 0 when
+// the conversion is not possible.
+func (h *WalletHandler) listingETH(item WalletItem, currency coinbase.FiatSymbol, ethSpot float64) float64 {
+	price := h.orders.getPrice(*item.Order)
+	symbol := h.orders.getCryptoSymbol(item.Order.GetBuy().Type)
+	if symbol == coinbase.CryptoETH {
+		return price
+	}
+
+	if ethSpot == 0 {
+		return 0
+	}
+
+	return price * h.orders.spot.RetrieveSpotPrice(symbol, currency) / ethSpot
+}
+
+func (h *WalletHandler) HandleValueCommand(address string, currency coinbase.FiatSymbol, useListings bool) *discordgo.InteractionResponseData {
+	if !IsAddress(address) {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("%s is not a valid wallet address", address)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	items, err := h.GetInventory(ctx, address)
+	if err != nil {
+		log.Errorf("unable to retrieve inventory of %v: %v", address, err)
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to retrieve the inventory of %s", address)}
+	}
+
+	if len(items) == 0 {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("<%s> does not own any heroes or portals", GetImmutascanUserURL(address))}
+	}
+
+	valuation, err := h.Value(ctx, items, useListings, currency)
+	if err != nil {
+		log.Errorf("unable to value inventory of %v: %v", address, err)
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to value the inventory of %s", address)}
+	}
+
+	ethSpot := h.orders.spot.RetrieveSpotPrice(coinbase.CryptoETH, currency)
+
+	var fields []*discordgo.MessageEmbedField
+	for _, gv := range valuation.Groups {
+		floor := "No floor"
+		if gv.FloorETH > 0 {
+			floor = fmt.Sprintf("Floor %f ETH", gv.FloorETH)
+		}
+
+		value := fmt.Sprintf(
+			"%v owned, %s\n%f ETH / %s",
+			gv.Count,
+			floor,
+			gv.ValueETH,
+			FormatPrice(gv.ValueETH*ethSpot, currency),
+		)
+		if gv.Unvalued > 0 {
+			value += fmt.Sprintf("\n%v without a price", gv.Unvalued)
+		}
+
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s", gv.Rarity, gv.Collection),
+			Value: value,
+		})
+	}
+
+	description := fmt.Sprintf(
+		"Total: %f ETH / %s for %v NFTs",
+		valuation.ValueETH,
+		FormatPrice(valuation.ValueETH*ethSpot, currency),
+		valuation.Count,
+	)
+	if valuation.Unvalued > 0 {
+		description += fmt.Sprintf(" (%v could not be valued)", valuation.Unvalued)
+	}
+
+	basis := "rarity floors"
+	if useListings {
+		basis = "own listings and rarity floors"
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("Estimated value of %s from %s", address, basis),
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Portfolio Valuation (Estimate, Fees Included)",
+				URL:         GetImmutascanUserURL(address),
+				Description: description,
+				Fields:      fields,
+				Timestamp:   time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
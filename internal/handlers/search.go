@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+type SearchHandler struct {
+	col    data.BitVerseCollection
+	index  *index.Index
+	orders *OrdersHandler
+}
+
+func NewSearchHandler(col data.BitVerseCollection, idx *index.Index, orders *OrdersHandler) *SearchHandler {
+	return &SearchHandler{col: col, index: idx, orders: orders}
+}
+
+// HandleCommand lists up to limit assets matching the query with their owner
+// and cheapest active listing.
+func (h *SearchHandler) HandleCommand(q index.Query, limit int, currency coinbase.FiatSymbol) *discordgo.InteractionResponseData {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if h.index == nil || !h.index.Loaded() {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("The %s index is still loading, try again in a few minutes", h.col.Singular)}
+	}
+
+	matches := h.index.Find(q)
+	if len(matches) == 0 {
+		return &discordgo.InteractionResponseData{Content: "No results found"}
+	}
+
+	lines := []string{fmt.Sprintf("%v matching %s:", len(matches), h.col.Name)}
+	if len(matches) > limit {
+		lines[0] = fmt.Sprintf("%v matching %s, showing the first %v:", len(matches), h.col.Name, limit)
+		matches = matches[:limit]
+	}
+
+	tokenIDs := make([]string, 0, len(matches))
+	for _, e := range matches {
+		tokenIDs = append(tokenIDs, e.TokenID)
+	}
+
+	listings, err := h.orders.GetListings(ctx, h.col.Address, tokenIDs)
+	if err != nil {
+		log.Errorf("unable to retrieve listings for %v: %v", tokenIDs, err)
+	}
+
+	for _, e := range matches {
+		listing := "Not listed"
+		if err != nil {
+			listing = "Listing unknown"
+		} else if order, ok := listings[e.TokenID]; ok {
+			listing = "Listed for " + h.orders.FormatOrderPrice(order, currency)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"• __%s__ #%s (%s, Level %s)\n  %s\n  Owner: <%s>\n  Link: <%s>",
+			e.HeroName(),
+			e.TokenID,
+			e.Rarity(),
+			e.Level(),
+			listing,
+			GetImmutascanUserURL(e.Owner),
+			GetImmutascanAssetURL(h.col.Address, e.TokenID),
+		))
+	}
+
+	var content string
+	for i, line := range lines {
+		if len(line)+len(content) > MaxContentLength {
+			content += "\n... (Max Discord length reached)"
+			break
+		}
+
+		if i == 0 {
+			content = line
+		} else {
+			content += "\n\n" + line
+		}
+	}
+
+	return &discordgo.InteractionResponseData{Content: strings.TrimSpace(content)}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// newTestSearchHandler indexes heroes 1 to n and lists the ones in listed,
+// each for its token ID in hundredths of an ETH.
+func newTestSearchHandler(t *testing.T, n int, listed ...int) (*SearchHandler, *fake.Orders) {
+	t.Helper()
+
+	col := data.BitVerseCollections[data.CollectionHero]
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	assets := fake.NewAssets()
+	for i := 1; i <= n; i++ {
+		tokenID := fmt.Sprint(i)
+		assets.Put(fake.Asset(col.Address, tokenID, "0x2222222222222222222222222222222222222222", map[string]interface{}{
+			data.MetadataRarity:   "Common",
+			data.MetadataHeroName: "Hero " + tokenID,
+		}))
+	}
+
+	list := fake.NewOrders(assets)
+	for _, i := range listed {
+		list.Put(fake.Order(int32(i), col.Address, fmt.Sprint(i), float64(i)/100, start))
+	}
+
+	spot := fake.NewSpotPrices()
+	spot.Set(coinbase.CryptoETH, coinbase.FiatUSD, 2000)
+
+	cm := fake.NewClientsManager(list, assets, spot)
+	idx := index.New(cm, col, filepath.Join(t.TempDir(), index.DefaultHeroesFile))
+	if err := idx.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSearchHandler(col, idx, NewOrdersHandler(cm, nil)), list
+}
+
+func TestSearchListingsInOneRequest(t *testing.T) {
+	h, list := newTestSearchHandler(t, 3, 1, 3)
+
+	text := h.HandleCommand(index.Query{}, 25, coinbase.FiatUSD).Content
+	for _, want := range []string{
+		"#1 (Common, Level )\n  Listed for 0.010000 ETH / $20.00",
+		"#2 (Common, Level )\n  Not listed",
+		"#3 (Common, Level )\n  Listed for 0.030000 ETH / $60.00",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("%q missing from:\n%v", want, text)
+		}
+	}
+
+	if list.Calls != 1 {
+		t.Errorf("made %v order requests, want 1", list.Calls)
+	}
+}
+
+func TestSearchListingsBeyondOnePage(t *testing.T) {
+	// Every hero is listed, so the cheapest page misses the last ones.
+	listed := make([]int, MaxOrderResults+5)
+	for i := range listed {
+		listed[i] = i + 1
+	}
+	h, list := newTestSearchHandler(t, len(listed), listed...)
+
+	q := index.Query{Name: fmt.Sprintf("Hero %v", len(listed))}
+	text := h.HandleCommand(q, 25, coinbase.FiatUSD).Content
+	if want := fmt.Sprintf("Listed for %.6f ETH", float64(len(listed))/100); !strings.Contains(text, want) {
+		t.Errorf("%q missing from:\n%v", want, text)
+	}
+
+	if list.Calls != 2 {
+		t.Errorf("made %v order requests, want 2", list.Calls)
+	}
+}
+
+func TestSearchListingsUnknown(t *testing.T) {
+	h, list := newTestSearchHandler(t, 2, 1)
+	list.Err = errors.New("unavailable")
+
+	text := h.HandleCommand(index.Query{}, 25, coinbase.FiatUSD).Content
+	if got := strings.Count(text, "Listing unknown"); got != 2 {
+		t.Errorf("got %v unknown listings, want 2:\n%v", got, text)
+	}
+}
+
+func TestSearchIndexLoading(t *testing.T) {
+	col := data.BitVerseCollections[data.CollectionHero]
+	cm := fake.NewClientsManager(nil, nil, nil)
+	idx := index.New(cm, col, filepath.Join(t.TempDir(), index.DefaultHeroesFile))
+
+	for _, h := range []*SearchHandler{NewSearchHandler(col, idx, NewOrdersHandler(cm, nil)), NewSearchHandler(col, nil, NewOrdersHandler(cm, nil))} {
+		if got := h.HandleCommand(index.Query{}, 25, coinbase.FiatUSD).Content; !strings.Contains(got, "index is still loading") {
+			t.Errorf("got %q before the index loaded", got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/deadloct/bitverse-nft-bot/internal/data"
 
+	"github.com/deadloct/immutablex-go-lib/coinbase"
 	"github.com/deadloct/immutablex-go-lib/utils"
 )
 
@@ -15,10 +16,29 @@ const (
 	OrderTokenTroveURLFormat   = "https://tokentrove.com/collection/%s/imx-%s"
 )
 
+func FormatPrice(price float64, fiat coinbase.FiatSymbol) string {
+	var symbol string
+
+	switch fiat {
+	case coinbase.FiatEUR:
+		symbol = "€"
+	case coinbase.FiatGBP:
+		symbol = "£"
+	default:
+		symbol = "$"
+	}
+
+	return fmt.Sprintf("%s%0.2f", symbol, price)
+}
+
 func GetImmutascanUserURL(address string) string {
 	return strings.Join([]string{utils.ImmutascanURL, "address", address}, "/")
 }
 
+func GetImmutascanOrderURL(orderID int32) string {
+	return strings.Join([]string{utils.ImmutascanURL, "order", fmt.Sprint(orderID)}, "/")
+}
+
 func GetImmutascanAssetURL(tokenAddress string, tokenID string) string {
 	return strings.Join([]string{
 		utils.ImmutascanURL,
@@ -29,14 +49,16 @@ func GetImmutascanAssetURL(tokenAddress string, tokenID string) string {
 }
 
 func GetTokenTroveAssetURL(tokenAddress string, tokenID string) string {
-	switch tokenAddress {
-	case data.BitVerseCollections["hero"].Address:
-		return fmt.Sprintf(OrderTokenTroveURLFormat, "BitverseHeroes", tokenID)
-	case data.BitVerseCollections["portal"].Address:
-		return "Portals are grouped by rarity on TokenTrove not individually"
-	default:
+	col, ok := data.CollectionByAddress(tokenAddress)
+	if !ok {
 		return ""
 	}
+
+	if col.TokenTroveSlug == "" {
+		return col.TokenTroveNote
+	}
+
+	return fmt.Sprintf(OrderTokenTroveURLFormat, col.TokenTroveSlug, tokenID)
 }
 
 // Samples:
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const WalletPageSize = 200
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// WalletItem is a single NFT owned by a wallet. Order is the cheapest active
+// listing of the token by the owner, or nil if it is not listed.
+type WalletItem struct {
+	Collection data.BitVerseCollection
+	TokenID    string
+	Name       string
+	Rarity     string
+	Order      *imxapi.Order
+}
+
+type WalletHandler struct {
+	cm     *api.ClientsManager
+	orders *OrdersHandler
+}
+
+func NewWalletHandler(cm *api.ClientsManager, orders *OrdersHandler) *WalletHandler {
+	return &WalletHandler{cm: cm, orders: orders}
+}
+
+func IsAddress(str string) bool {
+	return addressPattern.MatchString(str)
+}
+
+// GetInventory returns every NFT of the known collections owned by the
+// address, sorted by collection, rarity and token ID.
+func (h *WalletHandler) GetInventory(ctx context.Context, address string) ([]WalletItem, error) {
+	address = strings.ToLower(address)
+
+	var items []WalletItem
+	for _, col := range data.Collections() {
+		listings, err := h.getListings(ctx, col, address)
+		if err != nil {
+			return nil, err
+		}
+
+		var owned []WalletItem
+		cfg := &assets.ListAssetsConfig{
+			Collection: col.Address,
+			PageSize:   WalletPageSize,
+			User:       address,
+		}
+
+		for {
+			resp, err := h.cm.AssetsClient.ListAssets(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, asset := range resp.Result {
+				item := WalletItem{
+					Collection: col,
+					TokenID:    asset.TokenId,
+					Name:       asset.GetName(),
+					Rarity:     "Unknown",
+				}
+
+				if rarity, ok := asset.GetMetadata()[col.RarityKey()].(string); ok && rarity != "" {
+					item.Rarity = rarity
+				}
+
+				if order, ok := listings[asset.TokenId]; ok {
+					item.Order = &order
+				}
+
+				owned = append(owned, item)
+			}
+
+			if resp.Remaining == 0 || resp.Cursor == "" {
+				break
+			}
+			cfg.Cursor = resp.Cursor
+		}
+
+		sort.Slice(owned, func(i, j int) bool {
+			ri, rj := rarityRank(col, owned[i].Rarity), rarityRank(col, owned[j].Rarity)
+			if ri != rj {
+				return ri < rj
+			}
+
+			return tokenIDLess(owned[i].TokenID, owned[j].TokenID)
+		})
+
+		items = append(items, owned...)
+	}
+
+	return items, nil
+}
+
+// getListings returns the cheapest active listing by the address of each of
+// its tokens in the collection.
+func (h *WalletHandler) getListings(ctx context.Context, col data.BitVerseCollection, address string) (map[string]imxapi.Order, error) {
+	result, err := h.cm.OrdersClient.ListOrders(ctx, &orders.ListOrdersConfig{
+		PageSize:         MaxOrderResults,
+		SellTokenAddress: col.Address,
+		Status:           "active",
+		User:             address,
+		OrderBy:          "buy_quantity_with_fees",
+		Direction:        "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listings := make(map[string]imxapi.Order)
+	for _, order := range result {
+		tokenID := order.Sell.Data.GetTokenId()
+		if _, ok := listings[tokenID]; !ok {
+			listings[tokenID] = order
+		}
+	}
+
+	return listings, nil
+}
+
+func (h *WalletHandler) HandleCommand(address string, currency coinbase.FiatSymbol) *discordgo.InteractionResponseData {
+	if !IsAddress(address) {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("%s is not a valid wallet address", address)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	items, err := h.GetInventory(ctx, address)
+	if err != nil {
+		log.Errorf("unable to retrieve inventory of %v: %v", address, err)
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("Unable to retrieve the inventory of %s", address)}
+	}
+
+	if len(items) == 0 {
+		return &discordgo.InteractionResponseData{Content: fmt.Sprintf("<%s> does not own any heroes or portals", GetImmutascanUserURL(address))}
+	}
+
+	lines := []string{fmt.Sprintf("Inventory of <%s>", GetImmutascanUserURL(address))}
+	for _, group := range groupWalletItems(items) {
+		first := group[0]
+		var owned, listed []string
+		for _, item := range group {
+			owned = append(owned, "#"+item.TokenID)
+			if item.Order != nil {
+				listed = append(listed, fmt.Sprintf("  #%s listed for %s", item.TokenID, h.orders.FormatOrderPrice(*item.Order, currency)))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"__%s %s__ ×%v (%v listed): %s",
+			first.Rarity,
+			first.Collection.Name,
+			len(group),
+			len(listed),
+			strings.Join(owned, ", "),
+		))
+		lines = append(lines, listed...)
+	}
+
+	var content string
+	for i, line := range lines {
+		if len(line)+len(content) > MaxContentLength {
+			content += "\n... (Max Discord length reached)"
+			break
+		}
+
+		if i == 0 {
+			content = line
+		} else {
+			content += "\n" + line
+		}
+	}
+
+	return &discordgo.InteractionResponseData{Content: content}
+}
+
+// groupWalletItems splits sorted items into runs of the same collection and
+// rarity.
+func groupWalletItems(items []WalletItem) [][]WalletItem {
+	var groups [][]WalletItem
+	for _, item := range items {
+		n := len(groups)
+		if n > 0 && groups[n-1][0].Collection.Address == item.Collection.Address && groups[n-1][0].Rarity == item.Rarity {
+			groups[n-1] = append(groups[n-1], item)
+			continue
+		}
+
+		groups = append(groups, []WalletItem{item})
+	}
+
+	return groups
+}
+
+// rarityRank orders rarities as listed by the collection, with unknown values
+// last.
+func rarityRank(col data.BitVerseCollection, rarity string) int {
+	rarities := col.RarityValues()
+	for i, r := range rarities {
+		if r == rarity {
+			return i
+		}
+	}
+
+	return len(rarities)
+}
+
+func tokenIDLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+
+	return x < y
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// Windows are the periods reported by /history.
+var Windows = []Window{
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+}
+
+type Window struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Stats summarizes the samples in a window. Prices are in the crypto symbol
+// of the samples, or in fiat when computed with a fiat currency.
+type Stats struct {
+	Count     int
+	Min       float64
+	Max       float64
+	Avg       float64
+	First     float64
+	Last      float64
+	Change    float64
+	ChangePct float64
+}
+
+// Value returns the sample's price in fiat, or in crypto when fiat is empty.
+func (s Sample) Value(fiat coinbase.FiatSymbol) float64 {
+	if fiat == "" {
+		return s.Price
+	}
+
+	return s.Fiat[fiat]
+}
+
+// Compute returns the stats for samples, which must be sorted oldest first.
+func Compute(samples []Sample, fiat coinbase.FiatSymbol) Stats {
+	var stats Stats
+	var sum float64
+	for i, sample := range samples {
+		v := sample.Value(fiat)
+		if i == 0 || v < stats.Min {
+			stats.Min = v
+		}
+		if i == 0 || v > stats.Max {
+			stats.Max = v
+		}
+
+		sum += v
+		stats.Count++
+	}
+
+	if stats.Count == 0 {
+		return stats
+	}
+
+	stats.Avg = sum / float64(stats.Count)
+	stats.First = samples[0].Value(fiat)
+	stats.Last = samples[len(samples)-1].Value(fiat)
+	stats.Change = stats.Last - stats.First
+	if stats.First != 0 {
+		stats.ChangePct = stats.Change / stats.First * 100
+	}
+
+	return stats
+}
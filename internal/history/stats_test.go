@@ -0,0 +1,68 @@
+package history
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore(DefaultRetention)
+	for _, p := range []struct {
+		age   time.Duration
+		price float64
+	}{
+		{age: 20 * 24 * time.Hour, price: 4},
+		{age: 3 * 24 * time.Hour, price: 1},
+		{age: 12 * time.Hour, price: 2},
+		{age: time.Hour, price: 3},
+	} {
+		s.Add(sample("Common", now.Add(-p.age), p.price))
+	}
+
+	tests := []struct {
+		window Window
+		fiat   coinbase.FiatSymbol
+		want   Stats
+	}{
+		{window: Windows[0], want: Stats{Count: 2, Min: 2, Max: 3, Avg: 2.5, First: 2, Last: 3, Change: 1, ChangePct: 50}},
+		{window: Windows[1], want: Stats{Count: 3, Min: 1, Max: 3, Avg: 2, First: 1, Last: 3, Change: 2, ChangePct: 200}},
+		{window: Windows[2], want: Stats{Count: 4, Min: 1, Max: 4, Avg: 2.5, First: 4, Last: 3, Change: -1, ChangePct: -25}},
+		{window: Windows[0], fiat: coinbase.FiatUSD, want: Stats{Count: 2, Min: 4000, Max: 6000, Avg: 5000, First: 4000, Last: 6000, Change: 2000, ChangePct: 50}},
+		// Samples without the currency count as 0.
+		{window: Windows[0], fiat: coinbase.FiatGBP, want: Stats{Count: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.window.Name+" "+string(tt.fiat), func(t *testing.T) {
+			got := Compute(s.Query("hero", "Common", now.Add(-tt.window.Duration)), tt.fiat)
+			if !approxEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeEmpty(t *testing.T) {
+	if got := Compute(nil, ""); got != (Stats{}) {
+		t.Errorf("got %+v for no samples, want zero stats", got)
+	}
+}
+
+func approxEqual(a, b Stats) bool {
+	values := func(s Stats) []float64 {
+		return []float64{float64(s.Count), s.Min, s.Max, s.Avg, s.First, s.Last, s.Change, s.ChangePct}
+	}
+
+	va, vb := values(a), values(b)
+	for i := range va {
+		if math.Abs(va[i]-vb[i]) > 1e-9 {
+			return false
+		}
+	}
+
+	return true
+}
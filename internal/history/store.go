@@ -0,0 +1,188 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultHistoryFile = "history.jsonl"
+	DefaultRetention   = 90 * 24 * time.Hour
+)
+
+// Sample is the floor price of one rarity in a collection at a point in time.
+// Fiat prices are converted at the spot rate when the sample was taken.
+type Sample struct {
+	At         time.Time                       `json:"at"`
+	Collection string                          `json:"collection"`
+	Rarity     string                          `json:"rarity"`
+	TokenID    string                          `json:"token_id,omitempty"`
+	Price      float64                         `json:"price"`
+	Symbol     coinbase.CryptoSymbol           `json:"symbol"`
+	Fiat       map[coinbase.FiatSymbol]float64 `json:"fiat,omitempty"`
+}
+
+type Store interface {
+	Add(sample Sample) error
+	// Query returns the samples for the collection and rarity taken at or
+	// after since, oldest first.
+	Query(collection, rarity string, since time.Time) []Sample
+}
+
+// MemoryStore keeps samples in memory only.
+type MemoryStore struct {
+	samples   []Sample
+	retention time.Duration
+	mu        sync.RWMutex
+}
+
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{retention: retention}
+}
+
+func (s *MemoryStore) Add(sample Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(sample)
+	return nil
+}
+
+func (s *MemoryStore) Query(collection, rarity string, since time.Time) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].At.Before(since) })
+
+	var result []Sample
+	for _, sample := range s.samples[start:] {
+		if sample.Collection == collection && sample.Rarity == rarity {
+			result = append(result, sample)
+		}
+	}
+
+	return result
+}
+
+// add inserts the sample keeping samples sorted by time and drops the ones
+// older than the retention period.
+func (s *MemoryStore) add(sample Sample) {
+	i := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].At.After(sample.At) })
+	s.samples = append(s.samples, Sample{})
+	copy(s.samples[i+1:], s.samples[i:])
+	s.samples[i] = sample
+
+	if s.retention > 0 {
+		cutoff := time.Now().Add(-s.retention)
+		start := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].At.Before(cutoff) })
+		s.samples = s.samples[start:]
+	}
+}
+
+// FileStore is a MemoryStore backed by a JSON lines file that samples are
+// appended to. Expired samples are compacted away when the file is opened.
+type FileStore struct {
+	*MemoryStore
+	path string
+}
+
+func NewFileStore(path string, retention time.Duration) (*FileStore, error) {
+	s := &FileStore{MemoryStore: NewMemoryStore(retention), path: path}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var total int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			log.Warnf("skipping invalid history line in %v: %v", path, err)
+			continue
+		}
+
+		total++
+		s.add(sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	log.Infof("loaded %v price history samples from %v", len(s.samples), path)
+	if total > len(s.samples) {
+		if err := s.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Add(sample Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(sample)
+
+	line, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// compact rewrites the file with only the retained samples.
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, sample := range s.samples {
+		if err := enc.Encode(sample); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,178 @@
+package history
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+func sample(rarity string, at time.Time, price float64) Sample {
+	return Sample{
+		At:         at,
+		Collection: "hero",
+		Rarity:     rarity,
+		Price:      price,
+		Symbol:     coinbase.CryptoETH,
+		Fiat:       map[coinbase.FiatSymbol]float64{coinbase.FiatUSD: price * 2000},
+	}
+}
+
+func prices(samples []Sample) []float64 {
+	var result []float64
+	for _, s := range samples {
+		result = append(result, s.Price)
+	}
+
+	return result
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var n int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		n++
+	}
+
+	return n
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore(DefaultRetention)
+
+	// Added out of order, and with a sample of another rarity in between.
+	s.Add(sample("Common", now.Add(-1*time.Hour), 3))
+	s.Add(sample("Common", now.Add(-3*time.Hour), 1))
+	s.Add(sample("Rare", now.Add(-2*time.Hour), 10))
+	s.Add(sample("Common", now.Add(-2*time.Hour), 2))
+
+	tests := []struct {
+		name   string
+		rarity string
+		since  time.Time
+		want   []float64
+	}{
+		{name: "oldest first", rarity: "Common", since: now.Add(-24 * time.Hour), want: []float64{1, 2, 3}},
+		{name: "since is inclusive", rarity: "Common", since: now.Add(-2 * time.Hour), want: []float64{2, 3}},
+		{name: "other rarity", rarity: "Rare", since: now.Add(-24 * time.Hour), want: []float64{10}},
+		{name: "nothing since", rarity: "Common", since: now, want: nil},
+		{name: "unknown rarity", rarity: "Mythic", since: now.Add(-24 * time.Hour), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prices(s.Query("hero", tt.rarity, tt.since)); !equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRetention(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore(24 * time.Hour)
+
+	s.Add(sample("Common", now.Add(-48*time.Hour), 1))
+	s.Add(sample("Common", now.Add(-time.Hour), 2))
+
+	if got := prices(s.Query("hero", "Common", time.Time{})); !equal(got, []float64{2}) {
+		t.Errorf("got %v, want only the sample within retention", got)
+	}
+}
+
+func TestFileStoreAppendsAndReloads(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), DefaultHistoryFile)
+
+	s, err := NewFileStore(path, DefaultRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, price := range []float64{1, 2, 3} {
+		if err := s.Add(sample("Common", now.Add(time.Duration(i-3)*time.Hour), price)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Errorf("got %v lines, want one per sample", n)
+	}
+
+	loaded, err := NewFileStore(path, DefaultRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := loaded.Query("hero", "Common", time.Time{})
+	if !equal(prices(got), []float64{1, 2, 3}) {
+		t.Fatalf("got %v after reloading, want [1 2 3]", prices(got))
+	}
+	if usd := got[0].Fiat[coinbase.FiatUSD]; usd != 2000 {
+		t.Errorf("got fiat price %v after reloading, want 2000", usd)
+	}
+}
+
+func TestFileStoreCompactsOnOpen(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), DefaultHistoryFile)
+
+	s, err := NewFileStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(sample("Common", now.Add(-72*time.Hour), 1))
+	s.Add(sample("Common", now.Add(-48*time.Hour), 2))
+	s.Add(sample("Common", now.Add(-time.Hour), 3))
+
+	// An invalid line is skipped and dropped by the compaction.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	loaded, err := NewFileStore(path, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prices(loaded.Query("hero", "Common", time.Time{})); !equal(got, []float64{3}) {
+		t.Errorf("got %v, want only the sample within retention", got)
+	}
+	if n := countLines(t, path); n != 1 {
+		t.Errorf("got %v lines after compacting, want 1", n)
+	}
+
+	// Nothing to compact leaves the file as it is.
+	if _, err := NewFileStore(path, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 1 {
+		t.Errorf("got %v lines after reopening, want 1", n)
+	}
+}
@@ -0,0 +1,261 @@
+// Package index keeps a local copy of every asset in a collection so assets
+// can be searched by metadata without querying Immutable X each time.
+package index
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/jsonfile"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultHeroesFile = "heroes.json"
+	RefreshInterval   = 6 * time.Hour
+	CrawlPageSize     = 200
+)
+
+type Entry struct {
+	TokenID  string                 `json:"token_id"`
+	Name     string                 `json:"name"`
+	Owner    string                 `json:"owner"`
+	Status   string                 `json:"status"`
+	Metadata map[string]interface{} `json:"metadata"`
+
+	// col names the metadata keys read by the accessors below.
+	col data.BitVerseCollection
+}
+
+// HeroName returns the hero name from the metadata, or the asset name.
+func (e Entry) HeroName() string {
+	if name, ok := e.Metadata[e.col.HeroNameKey()].(string); ok && name != "" {
+		return name
+	}
+
+	return e.Name
+}
+
+func (e Entry) Level() string {
+	if v, ok := e.Metadata[e.col.LevelKey()]; ok {
+		return fmt.Sprint(v)
+	}
+
+	return ""
+}
+
+func (e Entry) Rarity() string {
+	if v, ok := e.Metadata[e.col.RarityKey()].(string); ok {
+		return v
+	}
+
+	return ""
+}
+
+type indexFile struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Index holds every asset of one collection. It is loaded from disk on start
+// and refreshed by crawling the collection in the background.
+type Index struct {
+	clients   *api.ClientsManager
+	col       data.BitVerseCollection
+	entries   map[string]Entry
+	path      string
+	rarity    *rarityStats
+	updatedAt time.Time
+	stop      chan struct{}
+	mu        sync.RWMutex
+}
+
+// New creates an index saved to path, or kept in memory only when path is
+// empty.
+func New(cm *api.ClientsManager, col data.BitVerseCollection, path string) *Index {
+	return &Index{
+		clients: cm,
+		col:     col,
+		entries: make(map[string]Entry),
+		path:    path,
+	}
+}
+
+func (x *Index) Start() error {
+	var file indexFile
+	if err := jsonfile.Load(x.path, &file); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	x.updatedAt = file.UpdatedAt
+	for _, e := range file.Entries {
+		e.col = x.col
+		x.entries[e.TokenID] = e
+	}
+	x.rarity = newRarityStats(x.col, x.entries)
+	x.mu.Unlock()
+	log.Infof("loaded %v %v from index %v", len(file.Entries), x.col.Name, x.path)
+
+	x.stop = make(chan struct{}, 1)
+	go func() {
+		// Crawl right away unless the saved index is recent enough.
+		wait := time.Until(file.UpdatedAt.Add(RefreshInterval))
+		if wait < 0 {
+			wait = 0
+		}
+
+		timer := time.NewTimer(wait)
+		for {
+			select {
+			case <-x.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+				if err := x.Refresh(context.Background()); err != nil {
+					log.Errorf("could not refresh %v index: %v", x.col.Name, err)
+				}
+				timer.Reset(RefreshInterval)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (x *Index) Stop() {
+	close(x.stop)
+}
+
+// Refresh crawls the whole collection and replaces the index.
+func (x *Index) Refresh(ctx context.Context) error {
+	log.Infof("crawling %v to rebuild the index", x.col.Name)
+
+	entries := make(map[string]Entry)
+	cfg := &assets.ListAssetsConfig{
+		Collection: x.col.Address,
+		PageSize:   CrawlPageSize,
+	}
+
+	for {
+		resp, err := x.clients.AssetsClient.ListAssets(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		for _, asset := range resp.Result {
+			entries[asset.TokenId] = Entry{
+				TokenID:  asset.TokenId,
+				Name:     asset.GetName(),
+				Owner:    asset.GetUser(),
+				Status:   asset.Status,
+				Metadata: asset.GetMetadata(),
+				col:      x.col,
+			}
+		}
+
+		if resp.Remaining == 0 || resp.Cursor == "" {
+			break
+		}
+		cfg.Cursor = resp.Cursor
+	}
+
+	file := indexFile{UpdatedAt: time.Now()}
+	for _, e := range entries {
+		file.Entries = append(file.Entries, e)
+	}
+
+	rarity := newRarityStats(x.col, entries)
+
+	x.mu.Lock()
+	x.entries = entries
+	x.rarity = rarity
+	x.updatedAt = file.UpdatedAt
+	x.mu.Unlock()
+
+	log.Infof("indexed %v %v", len(entries), x.col.Name)
+	return jsonfile.Save(x.path, file)
+}
+
+// Loaded reports whether the index holds a complete crawl, either saved from
+// an earlier run or finished since startup.
+func (x *Index) Loaded() bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return !x.updatedAt.IsZero()
+}
+
+func (x *Index) Get(tokenID string) (Entry, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	e, ok := x.entries[tokenID]
+	return e, ok
+}
+
+// All returns every entry sorted by token ID.
+func (x *Index) All() []Entry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	result := make([]Entry, 0, len(x.entries))
+	for _, e := range x.entries {
+		result = append(result, e)
+	}
+
+	sortByTokenID(result)
+	return result
+}
+
+// Search matches the query against token IDs and hero names, case
+// insensitively. Exact token IDs come first, then names starting with the
+// query, then names containing it.
+func (x *Index) Search(query string, limit int) []Entry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var exact, prefix, contains []Entry
+	for _, e := range x.entries {
+		name := strings.ToLower(e.HeroName())
+		switch {
+		case e.TokenID == query:
+			exact = append(exact, e)
+		case strings.HasPrefix(name, query) || strings.HasPrefix(e.TokenID, query):
+			prefix = append(prefix, e)
+		case strings.Contains(name, query):
+			contains = append(contains, e)
+		}
+	}
+
+	sortByTokenID(prefix)
+	sortByTokenID(contains)
+
+	result := append(append(exact, prefix...), contains...)
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+func sortByTokenID(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, _ := strconv.Atoi(entries[i].TokenID)
+		b, _ := strconv.Atoi(entries[j].TokenID)
+		return a < b
+	})
+}
@@ -0,0 +1,89 @@
+package index
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query filters index entries by metadata. Zero values match everything.
+type Query struct {
+	Name     string
+	Rarity   string
+	MinLevel int
+	MaxLevel int
+	// Traits maps metadata keys to values, both compared case insensitively.
+	Traits map[string]string
+}
+
+// ParseTraits parses "Key=Value" pairs separated by commas.
+func ParseTraits(str string) (map[string]string, error) {
+	traits := make(map[string]string)
+	for _, pair := range strings.Split(str, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("trait %q is not in the form Key=Value", strings.TrimSpace(pair))
+		}
+
+		traits[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return traits, nil
+}
+
+func (q Query) Matches(e Entry) bool {
+	if q.Name != "" && !strings.Contains(strings.ToLower(e.HeroName()), strings.ToLower(q.Name)) {
+		return false
+	}
+
+	if q.Rarity != "" && !strings.EqualFold(e.Rarity(), q.Rarity) {
+		return false
+	}
+
+	if q.MinLevel > 0 || q.MaxLevel > 0 {
+		level, err := strconv.ParseFloat(e.Level(), 64)
+		if err != nil {
+			return false
+		}
+		if q.MinLevel > 0 && level < float64(q.MinLevel) {
+			return false
+		}
+		if q.MaxLevel > 0 && level > float64(q.MaxLevel) {
+			return false
+		}
+	}
+
+	for k, v := range q.Traits {
+		if !matchesTrait(e.Metadata, k, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesTrait(metadata map[string]interface{}, key, value string) bool {
+	for k, v := range metadata {
+		if strings.EqualFold(k, key) && strings.EqualFold(fmt.Sprint(v), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Find returns the entries matching the query sorted by token ID.
+func (x *Index) Find(q Query) []Entry {
+	var result []Entry
+	for _, e := range x.All() {
+		if q.Matches(e) {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+package index
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+)
+
+// Score is the statistical rarity of a token: the sum over its traits of the
+// inverse of each trait value's frequency. Rank 1 is the rarest token.
+type Score struct {
+	Score float64
+	Rank  int
+}
+
+// rarityStats counts trait values across a collection and scores every token.
+type rarityStats struct {
+	total  int
+	counts map[string]map[string]int
+	scores map[string]Score
+}
+
+func traitValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// newRarityStats scores the entries on the traits of the collection, leaving
+// out names, images and the game traits that change over time.
+func newRarityStats(col data.BitVerseCollection, entries map[string]Entry) *rarityStats {
+	stats := &rarityStats{
+		total:  len(entries),
+		counts: make(map[string]map[string]int),
+		scores: make(map[string]Score, len(entries)),
+	}
+
+	for _, e := range entries {
+		for k, v := range e.Metadata {
+			if !col.IsTrait(k) {
+				continue
+			}
+
+			if stats.counts[k] == nil {
+				stats.counts[k] = make(map[string]int)
+			}
+			stats.counts[k][traitValue(v)]++
+		}
+	}
+
+	// A missing trait is its own value, so tokens without a common trait
+	// score higher.
+	for _, values := range stats.counts {
+		n := 0
+		for _, count := range values {
+			n += count
+		}
+		if n < stats.total {
+			values[""] += stats.total - n
+		}
+	}
+
+	ids := make([]string, 0, len(entries))
+	for id, e := range entries {
+		var score float64
+		for k, values := range stats.counts {
+			score += float64(stats.total) / float64(values[traitValue(e.Metadata[k])])
+		}
+
+		stats.scores[id] = Score{Score: score}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := stats.scores[ids[i]].Score, stats.scores[ids[j]].Score
+		if a != b {
+			return a > b
+		}
+
+		x, _ := strconv.Atoi(ids[i])
+		y, _ := strconv.Atoi(ids[j])
+		return x < y
+	})
+
+	for i, id := range ids {
+		s := stats.scores[id]
+		s.Rank = i + 1
+		stats.scores[id] = s
+	}
+
+	return stats
+}
+
+// Score returns the rarity score and rank of the token.
+func (x *Index) Score(tokenID string) (Score, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.rarity == nil {
+		return Score{}, false
+	}
+
+	s, ok := x.rarity.scores[tokenID]
+	return s, ok
+}
+
+// TraitFrequency returns the share of tokens, from 0 to 1, that have the
+// value for the trait. It is 0 for traits that are not scored.
+func (x *Index) TraitFrequency(key string, value interface{}) float64 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.rarity == nil || x.rarity.total == 0 {
+		return 0
+	}
+
+	return float64(x.rarity.counts[key][traitValue(value)]) / float64(x.rarity.total)
+}
+
+// Size returns the number of tokens in the index.
+func (x *Index) Size() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.entries)
+}
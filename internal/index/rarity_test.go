@@ -0,0 +1,140 @@
+package index
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+)
+
+// testEntries has unique names, images, hero names and levels, none of which
+// may count towards the scores.
+func testEntries() map[string]Entry {
+	traits := map[string]map[string]interface{}{
+		"9":  {"Element": "Fire", data.MetadataRarity: "Common"},
+		"10": {"Element": "Fire", data.MetadataRarity: "Common"},
+		"3":  {"Element": "Fire", data.MetadataRarity: "Rare"},
+		"4":  {"Element": "Water"},
+	}
+
+	entries := make(map[string]Entry, len(traits))
+	for id, metadata := range traits {
+		metadata["name"] = "Item #" + id
+		metadata["image_url"] = "https://example.com/" + id + ".png"
+		metadata[data.MetadataHeroName] = "Hero " + id
+		metadata[data.MetadataHeroLevel] = id
+		metadata[data.MetadataGamePrefix+"Wins"] = id
+		entries[id] = Entry{TokenID: id, Metadata: metadata}
+	}
+
+	return entries
+}
+
+func TestNewRarityStatsCountsOnlyTraits(t *testing.T) {
+	stats := newRarityStats(data.BitVerseCollections[data.CollectionHero], testEntries())
+
+	var keys []string
+	for k := range stats.counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if want := []string{"Element", data.MetadataRarity}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("scored traits %v, want %v", keys, want)
+	}
+
+	// The entry without a rarity counts as its own value.
+	want := map[string]int{"Common": 2, "Rare": 1, "": 1}
+	if got := stats.counts[data.MetadataRarity]; !reflect.DeepEqual(got, want) {
+		t.Errorf("rarity counts %v, want %v", got, want)
+	}
+}
+
+func TestNewRarityStatsRanks(t *testing.T) {
+	tests := []struct {
+		name   string
+		col    data.BitVerseCollection
+		scores map[string]float64
+		ranks  []string
+	}{
+		{
+			name: "every trait",
+			col:  data.BitVerseCollections[data.CollectionHero],
+			// Four tokens over the count of each value.
+			scores: map[string]float64{"9": 4.0/3 + 2, "10": 4.0/3 + 2, "3": 4.0/3 + 4, "4": 4 + 4},
+			// Ties go to the lower token ID.
+			ranks: []string{"4", "3", "9", "10"},
+		},
+		{
+			name:   "non-traits from the collection",
+			col:    data.BitVerseCollection{Metadata: data.MetadataKeys{NonTraits: []string{"name", "image_url", "Element"}}},
+			scores: map[string]float64{"9": 2, "10": 2, "3": 4, "4": 4},
+			ranks:  []string{"3", "4", "9", "10"},
+		},
+		{
+			name: "game prefix from the collection",
+			col:  data.BitVerseCollection{Metadata: data.MetadataKeys{GamePrefix: "Ele"}},
+			// The unique BHQ trait is no longer a game trait and adds 4.
+			scores: map[string]float64{"9": 6, "10": 6, "3": 8, "4": 8},
+			ranks:  []string{"3", "4", "9", "10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := newRarityStats(tt.col, testEntries())
+
+			for id, want := range tt.scores {
+				if got := stats.scores[id].Score; math.Abs(got-want) > 1e-9 {
+					t.Errorf("token %v scored %v, want %v", id, got, want)
+				}
+			}
+
+			for i, id := range tt.ranks {
+				if got := stats.scores[id].Rank; got != i+1 {
+					t.Errorf("token %v ranked %v, want %v", id, got, i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRarityStatsEmpty(t *testing.T) {
+	stats := newRarityStats(data.BitVerseCollections[data.CollectionHero], nil)
+	if stats.total != 0 || len(stats.scores) != 0 {
+		t.Errorf("got %v tokens and %v scores, want none", stats.total, len(stats.scores))
+	}
+}
+
+func TestIndexScoreAndFrequency(t *testing.T) {
+	x := &Index{col: data.BitVerseCollections[data.CollectionHero], entries: testEntries()}
+	x.rarity = newRarityStats(x.col, x.entries)
+
+	if s, ok := x.Score("4"); !ok || s.Rank != 1 {
+		t.Errorf("Score(4) = %+v, %v, want rank 1", s, ok)
+	}
+	if _, ok := x.Score("99"); ok {
+		t.Error("scored an unknown token")
+	}
+
+	tests := []struct {
+		key   string
+		value interface{}
+		want  float64
+	}{
+		{key: "Element", value: "Fire", want: 0.75},
+		{key: data.MetadataRarity, value: "Rare", want: 0.25},
+		{key: "name", value: "Item #4", want: 0},
+		{key: data.MetadataHeroLevel, value: 4, want: 0},
+	}
+	for _, tt := range tests {
+		if got := x.TraitFrequency(tt.key, tt.value); got != tt.want {
+			t.Errorf("TraitFrequency(%v, %v) = %v, want %v", tt.key, tt.value, got, tt.want)
+		}
+	}
+
+	if got := x.Size(); got != 4 {
+		t.Errorf("Size() = %v, want 4", got)
+	}
+}
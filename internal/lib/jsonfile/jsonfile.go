@@ -0,0 +1,60 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Load decodes the JSON file at path into v. A missing file, or an empty path
+// for data kept in memory only, is not an error and leaves v untouched.
+func Load(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(contents, v)
+}
+
+// Save encodes v as JSON and atomically replaces the file at path, creating
+// parent directories as needed. An empty path saves nothing.
+func Save(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+
+	contents, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
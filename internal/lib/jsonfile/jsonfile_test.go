@@ -0,0 +1,57 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "data.json")
+
+	var missing map[string]int
+	if err := Load(path, &missing); err != nil || missing != nil {
+		t.Fatalf("loaded %v, %v from a missing file, want nothing", missing, err)
+	}
+
+	want := map[string]int{"a": 1, "b": 2}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]int
+	if err := Load(path, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded %v, want %v", got, want)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %v files after Save, want only the saved one", len(entries))
+	}
+}
+
+func TestEmptyPath(t *testing.T) {
+	before, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Save("", map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadDir("."); len(after) != len(before) {
+		t.Errorf("Save with an empty path wrote %v files", len(after)-len(before))
+	}
+
+	v := map[string]int{"kept": 1}
+	if err := Load("", &v); err != nil || v["kept"] != 1 {
+		t.Errorf("Load with an empty path changed %v, %v", v, err)
+	}
+}
@@ -2,58 +2,59 @@ package logger
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
 	log "github.com/sirupsen/logrus"
 )
 
-func Debug(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Debug(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Debug(args...)
 }
 
-func Debugf(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Debugf(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Debugf(format, args...)
 }
 
-func Info(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Info(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Info(args...)
 }
 
-func Infof(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Infof(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Infof(format, args...)
 }
 
-func Warn(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Warn(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Warn(args...)
 }
 
-func Warnf(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Warnf(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Warnf(format, args...)
 }
 
-func Error(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Error(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Error(args...)
 }
 
-func Errorf(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Errorf(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Errorf(format, args...)
 }
 
-func Panic(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Panic(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Panic(args...)
 }
 
-func Panicf(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Panicf(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Panicf(format, args...)
 }
 
-func Fatal(sess *discordgo.Session, i *discordgo.Interaction, args ...interface{}) {
+func Fatal(sess discord.Transport, i *discordgo.Interaction, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Fatal(args...)
 }
 
-func Fatalf(sess *discordgo.Session, i *discordgo.Interaction, format string, args ...interface{}) {
+func Fatalf(sess discord.Transport, i *discordgo.Interaction, format string, args ...interface{}) {
 	log.WithFields(getFields(sess, i)).Fatalf(format, args...)
 }
 
-func getFields(sess *discordgo.Session, i *discordgo.Interaction) log.Fields {
+func getFields(sess discord.Transport, i *discordgo.Interaction) log.Fields {
 	guild, err := sess.Guild(i.GuildID)
 	if err != nil {
 		log.Errorf("could not retrieve guild info for guild %v: %v", i.GuildID, err)
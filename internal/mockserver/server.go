@@ -0,0 +1,220 @@
+// Package mockserver is a stand-in for the Immutable X and Coinbase REST APIs
+// that serves canned orders, assets and spot prices from fixture files. Point
+// the bot at it with the IMX_API_URL and COINBASE_API_URL env vars.
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	OrdersFile = "orders.json"
+	AssetsFile = "assets.json"
+	SpotFile   = "spot.json"
+)
+
+// Fixtures are the canned responses. Spot prices are keyed by pair, such as
+// "ETH-USD".
+type Fixtures struct {
+	Orders []imxapi.Order
+	Assets []imxapi.Asset
+	Spot   map[string]float64
+}
+
+// LoadFixtures reads orders.json, assets.json and spot.json from dir. Missing
+// files leave that part of the fixtures empty.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	f := &Fixtures{Spot: make(map[string]float64)}
+	for name, v := range map[string]interface{}{
+		OrdersFile: &f.Orders,
+		AssetsFile: &f.Assets,
+		SpotFile:   &f.Spot,
+	} {
+		path := filepath.Join(dir, name)
+		contents, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warnf("fixture %v not found", path)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(contents, v); err != nil {
+			return nil, fmt.Errorf("could not parse fixture %v: %w", path, err)
+		}
+	}
+
+	return f, nil
+}
+
+// Server answers the endpoints used by the rest package clients, filtering
+// the fixtures with the in-memory fakes.
+type Server struct {
+	assets *fake.Assets
+	mux    *http.ServeMux
+	orders *fake.Orders
+	spot   *fake.SpotPrices
+}
+
+func New(f *Fixtures) *Server {
+	s := &Server{
+		assets: fake.NewAssets(f.Assets...),
+		mux:    http.NewServeMux(),
+		spot:   fake.NewSpotPrices(),
+	}
+	s.orders = fake.NewOrders(s.assets, f.Orders...)
+
+	for pair, price := range f.Spot {
+		crypto, fiat, ok := strings.Cut(pair, "-")
+		if !ok {
+			log.Warnf("ignoring spot price of invalid pair %v", pair)
+			continue
+		}
+		s.spot.Set(coinbase.CryptoSymbol(crypto), coinbase.FiatSymbol(fiat), price)
+	}
+
+	s.mux.HandleFunc("GET /v1/orders", s.handleOrders)
+	s.mux.HandleFunc("GET /v1/assets", s.handleAssets)
+	s.mux.HandleFunc("GET /v1/assets/{address}/{id}", s.handleAsset)
+	s.mux.HandleFunc("GET /v2/prices/{pair}/spot", s.handleSpot)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log.Debugf("%v %v", r.Method, r.URL)
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pageSize, _ := strconv.Atoi(q.Get("page_size"))
+	cfg := &orders.ListOrdersConfig{
+		BuyTokenType:        q.Get("buy_token_type"),
+		Cursor:              q.Get("cursor"),
+		Direction:           q.Get("direction"),
+		OrderBy:             q.Get("order_by"),
+		PageSize:            pageSize,
+		SellMetadata:        q.Get("sell_metadata"),
+		SellTokenAddress:    q.Get("sell_token_address"),
+		SellTokenID:         q.Get("sell_token_id"),
+		Status:              q.Get("status"),
+		MinTimestamp:        q.Get("min_timestamp"),
+		UpdatedMinTimestamp: q.Get("updated_min_timestamp"),
+		User:                q.Get("user"),
+	}
+
+	resp, err := s.orders.ListOrdersPage(r.Context(), cfg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, resp)
+}
+
+// assetsParams are the query params of GET /v1/assets that the server
+// understands. Any other param is rejected rather than silently ignored.
+var assetsParams = map[string]bool{
+	"collection":  true,
+	"cursor":      true,
+	"direction":   true,
+	"metadata":    true,
+	"name":        true,
+	"order_by":    true,
+	"page_size":   true,
+	"sell_orders": true,
+	"status":      true,
+	"user":        true,
+}
+
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	for param := range q {
+		if !assetsParams[param] {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported query param %v", param))
+			return
+		}
+	}
+
+	pageSize, _ := strconv.Atoi(q.Get("page_size"))
+	cfg := &assets.ListAssetsConfig{
+		Collection: q.Get("collection"),
+		Cursor:     q.Get("cursor"),
+		Direction:  q.Get("direction"),
+		Metadata:   q.Get("metadata"),
+		Name:       q.Get("name"),
+		OrderBy:    q.Get("order_by"),
+		PageSize:   pageSize,
+		SellOrders: q.Get("sell_orders") == "true",
+		Status:     q.Get("status"),
+		User:       q.Get("user"),
+	}
+
+	resp, err := s.assets.ListAssets(r.Context(), cfg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) handleAsset(w http.ResponseWriter, r *http.Request) {
+	asset, err := s.assets.GetAsset(r.Context(), r.PathValue("address"), r.PathValue("id"), false)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(w, asset)
+}
+
+func (s *Server) handleSpot(w http.ResponseWriter, r *http.Request) {
+	crypto, fiat, ok := strings.Cut(r.PathValue("pair"), "-")
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid currency pair %v", r.PathValue("pair")))
+		return
+	}
+
+	price := s.spot.RetrieveSpotPrice(coinbase.CryptoSymbol(crypto), coinbase.FiatSymbol(fiat))
+	if price == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no spot price for %v", r.PathValue("pair")))
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]string{
+			"amount":   strconv.FormatFloat(price, 'f', -1, 64),
+			"base":     crypto,
+			"currency": fiat,
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("could not write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": http.StatusText(status), "message": err.Error()})
+}
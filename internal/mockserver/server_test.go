@@ -0,0 +1,363 @@
+package mockserver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/config"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/mockserver"
+	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+const (
+	fixturesDir = "../../testdata/mockserver"
+	owner       = "0x1111111111111111111111111111111111111111"
+)
+
+// newTestClients serves the fixtures and points the bot's clients at them
+// through the same env vars used to run the bot against the mock server.
+func newTestClients(t *testing.T) *api.ClientsManager {
+	t.Helper()
+
+	fixtures, err := mockserver.LoadFixtures(fixturesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mockserver.New(fixtures))
+	t.Cleanup(srv.Close)
+
+	t.Setenv(config.EnvKey("IMX_API_URL"), srv.URL)
+	t.Setenv(config.EnvKey("COINBASE_API_URL"), srv.URL)
+	return api.NewClientsManager()
+}
+
+func heroes() data.BitVerseCollection {
+	return data.BitVerseCollections[data.CollectionHero]
+}
+
+func TestListOrders(t *testing.T) {
+	cm := newTestClients(t)
+
+	tests := []struct {
+		name string
+		cfg  orders.ListOrdersConfig
+		want []int32
+	}{
+		{
+			name: "active heroes cheapest first",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: heroes().Address, Status: "active", OrderBy: "buy_quantity_with_fees", Direction: "asc"},
+			want: []int32{1001, 1002, 1003, 1005},
+		},
+		{
+			name: "filled",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: heroes().Address, Status: "filled"},
+			want: []int32{1004},
+		},
+		{
+			name: "by rarity",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: heroes().Address, Status: "active", SellMetadata: `{"Rarity":["Rare","Mythic"]}`},
+			want: []int32{1003, 1005},
+		},
+		{
+			name: "other collection",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: data.BitVerseCollections[data.CollectionPortal].Address},
+			want: []int32{1006},
+		},
+		{
+			name: "newest first since a time",
+			cfg:  orders.ListOrdersConfig{SellTokenAddress: heroes().Address, Status: "active", OrderBy: "created_at", Direction: "desc", MinTimestamp: "2026-10-02T12:00:00Z"},
+			want: []int32{1005, 1003, 1002},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := cm.OrdersClient.ListOrders(context.Background(), &tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int32
+			for _, order := range result {
+				got = append(got, order.OrderId)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got orders %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListOrdersPageFollowsCursor(t *testing.T) {
+	cm := newTestClients(t)
+	cfg := &orders.ListOrdersConfig{
+		PageSize:         2,
+		SellTokenAddress: heroes().Address,
+		Status:           "active",
+		OrderBy:          "buy_quantity_with_fees",
+		Direction:        "asc",
+	}
+
+	var pages [][]int32
+	for {
+		resp, err := cm.OrdersPager.ListOrdersPage(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var page []int32
+		for _, order := range resp.Result {
+			page = append(page, order.OrderId)
+		}
+		pages = append(pages, page)
+
+		if resp.Cursor == "" {
+			break
+		}
+		if len(pages) > 3 {
+			t.Fatalf("still paging after %v, cursor %q", pages, resp.Cursor)
+		}
+		cfg.Cursor = resp.Cursor
+	}
+
+	want := [][]int32{{1001, 1002}, {1003, 1005}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("got pages %v, want %v", pages, want)
+	}
+}
+
+func TestAssets(t *testing.T) {
+	cm := newTestClients(t)
+
+	asset, err := cm.AssetsClient.GetAsset(context.Background(), heroes().Address, "103", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := asset.GetMetadata()[data.MetadataRarity]; got != "Rare" {
+		t.Errorf("got rarity %v for hero 103, want Rare", got)
+	}
+
+	if _, err := cm.AssetsClient.GetAsset(context.Background(), heroes().Address, "999", true); err == nil {
+		t.Error("got no error for an unknown hero")
+	}
+
+	resp, err := cm.AssetsClient.ListAssets(context.Background(), &assets.ListAssetsConfig{Collection: heroes().Address, User: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, asset := range resp.Result {
+		ids = append(ids, asset.TokenId)
+	}
+	if want := []string{"101", "102", "103", "105"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got heroes %v owned by %v, want %v", ids, owner, want)
+	}
+}
+
+func TestListAssetsFilters(t *testing.T) {
+	cm := newTestClients(t)
+
+	tests := []struct {
+		name string
+		cfg  assets.ListAssetsConfig
+		want []string
+	}{
+		{
+			name: "metadata",
+			cfg:  assets.ListAssetsConfig{Collection: heroes().Address, Metadata: `{"Rarity":["Rare","Mythic"]}`},
+			want: []string{"103", "105"},
+		},
+		{
+			name: "name",
+			cfg:  assets.ListAssetsConfig{Name: "portal"},
+			want: []string{"7"},
+		},
+		{
+			name: "updated descending, ties by token ID",
+			cfg:  assets.ListAssetsConfig{Collection: heroes().Address, OrderBy: "updated_at", Direction: "desc"},
+			want: []string{"105", "104", "103", "102", "101"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := cm.AssetsClient.ListAssets(context.Background(), &tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, asset := range resp.Result {
+				got = append(got, asset.TokenId)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got assets %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := cm.AssetsClient.ListAssets(context.Background(), &assets.ListAssetsConfig{SellOrders: true}); err == nil {
+		t.Error("got no error asking for sell orders")
+	}
+}
+
+func TestListAssetsRejectsUnknownParams(t *testing.T) {
+	fixtures, err := mockserver.LoadFixtures(fixturesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(mockserver.New(fixtures))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/assets?collection=" + heroes().Address + "&rarity=Rare")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got status %v for an unknown param, want %v", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestSpotPrice(t *testing.T) {
+	cm := newTestClients(t)
+
+	if got := cm.SpotPriceClient.RetrieveSpotPrice(coinbase.CryptoETH, coinbase.FiatUSD); got != 2450.5 {
+		t.Errorf("got ETH-USD %v, want 2450.5", got)
+	}
+	if got := cm.SpotPriceClient.RetrieveSpotPrice(coinbase.CryptoETH, coinbase.FiatSymbol("JPY")); got != 0 {
+		t.Errorf("got ETH-JPY %v, want 0 for a pair without a fixture", got)
+	}
+}
+
+// TestMarket pages through the /market results the same way the Next button
+// does.
+func TestMarket(t *testing.T) {
+	h := handlers.NewOrdersHandler(newTestClients(t), nil)
+	cfg := &orders.ListOrdersConfig{
+		PageSize:         2,
+		SellTokenAddress: heroes().Address,
+		Status:           "active",
+		OrderBy:          "buy_quantity_with_fees",
+		Direction:        "asc",
+	}
+
+	var content []string
+	start := handlers.PageCursor{}
+	for page := 0; ; page++ {
+		if page > 4 {
+			t.Fatalf("still paging at %#v", start)
+		}
+
+		response, next := h.HandlePage(cfg, "summary", coinbase.FiatUSD, start)
+		content = append(content, response.Content)
+		if next == nil {
+			break
+		}
+		start = *next
+	}
+
+	text := strings.Join(content, "\n")
+	last := -1
+	for _, id := range []string{"101", "102", "103", "105"} {
+		i := strings.Index(text, "#"+id)
+		if i < 0 {
+			t.Fatalf("hero %v missing from:\n%v", id, text)
+		}
+		if i < last {
+			t.Errorf("hero %v out of price order in:\n%v", id, text)
+		}
+		last = i
+	}
+	if strings.Contains(text, "#104") {
+		t.Errorf("sold hero 104 listed in:\n%v", text)
+	}
+	if !strings.Contains(text, handlers.FormatPrice(0.06*2450.5, coinbase.FiatUSD)) {
+		t.Errorf("price of hero 101 in USD missing from:\n%v", text)
+	}
+}
+
+func TestHero(t *testing.T) {
+	h := handlers.NewAssetMessageHandler(heroes(), newTestClients(t), nil)
+
+	response := h.HandleCommand("105")
+	if len(response.Embeds) != 1 {
+		t.Fatalf("got %v embeds, want 1: %q", len(response.Embeds), response.Content)
+	}
+
+	if !strings.Contains(response.Content, "105") {
+		t.Errorf("got title %q, want hero 105", response.Content)
+	}
+
+	var fields []string
+	for _, f := range response.Embeds[0].Fields {
+		fields = append(fields, f.Name+": "+f.Value)
+	}
+	if text := strings.Join(fields, "\n"); !strings.Contains(text, "Mythic") || !strings.Contains(text, owner) {
+		t.Errorf("rarity or owner missing from fields:\n%v", text)
+	}
+
+	if response := h.HandleCommand("999"); len(response.Embeds) != 0 || !strings.Contains(response.Content, "999") {
+		t.Errorf("got %q for an unknown hero", response.Content)
+	}
+}
+
+// TestCheapestWatcher runs a watcher until it DMs the cheapest common hero.
+func TestCheapestWatcher(t *testing.T) {
+	cm := newTestClients(t)
+	recorder := discord.NewRecorder()
+	subs, err := notifier.NewSubscriptions(filepath.Join(t.TempDir(), notifier.DefaultSubscriptionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := notifier.WatcherConfig{
+		Name:       "common",
+		Collection: data.CollectionHero,
+		Rarity:     []string{"Common"},
+		Threshold:  250,
+		Users:      []string{"42"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := notifier.NewWatcher(cm, recorder, cfg, notifier.NewMemorySeenStore(notifier.DefaultSeenTTL), subs, nil)
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	var msgs []string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		for _, msg := range recorder.MessagesTo(discord.DMChannelPrefix + "42") {
+			msgs = append(msgs, msg.Content)
+		}
+		if len(msgs) > 0 {
+			break
+		}
+	}
+
+	if len(msgs) != 1 {
+		t.Fatalf("got %v DMs, want 1", len(msgs))
+	}
+	if !strings.Contains(msgs[0], "token id: 101") {
+		t.Errorf("DM is not about hero 101:\n%v", msgs[0])
+	}
+}
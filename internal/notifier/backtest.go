@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// BacktestNotification is a notification a watcher would have sent.
+type BacktestNotification struct {
+	At        time.Time
+	Rarity    string
+	TokenID   string
+	Price     float64
+	Symbol    coinbase.CryptoSymbol
+	FiatPrice float64
+}
+
+// BacktestResult summarizes a watcher config run over the price history.
+type BacktestResult struct {
+	Checks        int
+	Notifications []BacktestNotification
+
+	// Lowest is the cheapest floor seen, whether it was notified or not, to
+	// help pick a threshold.
+	Lowest *BacktestNotification
+}
+
+// Backtest replays the floor samples recorded since the given time through
+// the cheapest listing check of the watcher config. The history only holds
+// the ETH floor of each rarity, so other modes, buy currencies and metadata
+// filters cannot be backtested.
+func Backtest(cfg WatcherConfig, store history.Store, since time.Time) (*BacktestResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.Mode != ModeCheapest:
+		return nil, fmt.Errorf("only the %v mode can be backtested", ModeCheapest)
+	case cfg.BuyTokenType != handlers.TokenTypeETH:
+		return nil, fmt.Errorf("only %v listings are recorded in the history", handlers.TokenTypeETH)
+	case len(cfg.Metadata) > 0:
+		return nil, fmt.Errorf("metadata filters cannot be backtested, the history only records the floor of each rarity")
+	}
+
+	rarities := cfg.Rarity
+	if len(rarities) == 0 {
+		rarities = data.BitVerseCollections[cfg.Collection].RarityValues()
+	}
+
+	// The sampler records every rarity at the same time, so group the
+	// samples into the checks the watcher would have made.
+	var times []time.Time
+	checks := make(map[time.Time][]history.Sample)
+	for _, rarity := range rarities {
+		for _, sample := range store.Query(cfg.Collection, rarity, since) {
+			if _, ok := checks[sample.At]; !ok {
+				times = append(times, sample.At)
+			}
+			checks[sample.At] = append(checks[sample.At], sample)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	result := &BacktestResult{Checks: len(times)}
+	seen := make(map[string]time.Time)
+	for _, at := range times {
+		// The watcher only looks at the single cheapest listing across all of
+		// its rarities.
+		var cheapest *history.Sample
+		for i, sample := range checks[at] {
+			if cheapest == nil || sample.Value(cfg.Currency) < cheapest.Value(cfg.Currency) {
+				cheapest = &checks[at][i]
+			}
+		}
+
+		n := BacktestNotification{
+			At:        at,
+			Rarity:    cheapest.Rarity,
+			TokenID:   cheapest.TokenID,
+			Price:     cheapest.Price,
+			Symbol:    cheapest.Symbol,
+			FiatPrice: cheapest.Value(cfg.Currency),
+		}
+
+		if result.Lowest == nil || n.FiatPrice < result.Lowest.FiatPrice {
+			lowest := n
+			result.Lowest = &lowest
+		}
+
+		if n.FiatPrice > cfg.Threshold {
+			continue
+		}
+
+		// Same dedupe as the seen store: a token is announced again only at
+		// a new price or once the earlier notification has expired.
+		key := fmt.Sprintf("%v:%v", n.TokenID, n.Price)
+		if last, ok := seen[key]; ok && at.Sub(last) <= DefaultSeenTTL {
+			continue
+		}
+		seen[key] = at
+
+		result.Notifications = append(result.Notifications, n)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,160 @@
+package notifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// floor is a recorded floor, hours after the start of the test history.
+type floor struct {
+	hours   int
+	rarity  string
+	tokenID string
+	usd     float64
+}
+
+func newTestHistory(start time.Time, floors []floor) *history.MemoryStore {
+	store := history.NewMemoryStore(history.DefaultRetention)
+	for _, f := range floors {
+		store.Add(history.Sample{
+			At:         start.Add(time.Duration(f.hours) * time.Hour),
+			Collection: data.CollectionHero,
+			Rarity:     f.rarity,
+			TokenID:    f.tokenID,
+			Price:      f.usd / 2000,
+			Symbol:     coinbase.CryptoETH,
+			Fiat:       map[coinbase.FiatSymbol]float64{coinbase.FiatUSD: f.usd},
+		})
+	}
+
+	return store
+}
+
+func TestBacktest(t *testing.T) {
+	ttl := int(DefaultSeenTTL / time.Hour)
+
+	tests := []struct {
+		name   string
+		rarity []string
+		floors []floor
+		since  int
+		checks int
+		notify []string
+		lowest float64
+	}{
+		{
+			name:   "crossing the threshold",
+			floors: []floor{{0, "Common", "1", 120}, {1, "Common", "2", 90}, {2, "Common", "3", 130}, {3, "Common", "4", 100}},
+			checks: 4,
+			notify: []string{"2", "4"},
+			lowest: 90,
+		},
+		{
+			name:   "same listing once",
+			floors: []floor{{0, "Common", "1", 90}, {1, "Common", "1", 90}, {2, "Common", "2", 120}, {3, "Common", "1", 90}},
+			checks: 4,
+			notify: []string{"1"},
+			lowest: 90,
+		},
+		{
+			name:   "same token at a new price",
+			floors: []floor{{0, "Common", "1", 90}, {1, "Common", "1", 80}},
+			checks: 2,
+			notify: []string{"1", "1"},
+			lowest: 80,
+		},
+		{
+			name:   "same listing after the seen TTL",
+			floors: []floor{{0, "Common", "1", 90}, {ttl, "Common", "1", 90}, {ttl + 1, "Common", "1", 90}},
+			checks: 3,
+			notify: []string{"1", "1"},
+			lowest: 90,
+		},
+		{
+			name:   "cheapest of every rarity",
+			floors: []floor{{0, "Common", "1", 95}, {0, "Rare", "2", 80}, {1, "Common", "1", 95}, {1, "Rare", "3", 150}},
+			checks: 2,
+			notify: []string{"2", "1"},
+			lowest: 80,
+		},
+		{
+			name:   "only the watched rarities",
+			rarity: []string{"Common"},
+			floors: []floor{{0, "Common", "1", 120}, {0, "Rare", "2", 80}},
+			checks: 1,
+			lowest: 120,
+		},
+		{
+			name:   "window starts at since",
+			floors: []floor{{0, "Common", "1", 50}, {1, "Common", "2", 90}, {2, "Common", "3", 110}},
+			since:  1,
+			checks: 2,
+			notify: []string{"2"},
+			lowest: 90,
+		},
+		{
+			name:   "empty window",
+			floors: []floor{{0, "Common", "1", 50}},
+			since:  1,
+		},
+	}
+
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Hour)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := WatcherConfig{Name: "test", Rarity: tt.rarity, Threshold: 100}
+			result, err := Backtest(cfg, newTestHistory(start, tt.floors), start.Add(time.Duration(tt.since)*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Checks != tt.checks {
+				t.Errorf("got %v checks, want %v", result.Checks, tt.checks)
+			}
+
+			var notified []string
+			for _, n := range result.Notifications {
+				notified = append(notified, n.TokenID)
+			}
+			if !reflect.DeepEqual(notified, tt.notify) {
+				t.Errorf("notified %v, want %v", notified, tt.notify)
+			}
+
+			switch {
+			case tt.lowest == 0 && result.Lowest != nil:
+				t.Errorf("got lowest %+v, want none", *result.Lowest)
+			case tt.lowest != 0 && (result.Lowest == nil || result.Lowest.FiatPrice != tt.lowest):
+				t.Errorf("got lowest %+v, want %v", result.Lowest, tt.lowest)
+			}
+		})
+	}
+}
+
+func TestBacktestRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  WatcherConfig
+		err  string
+	}{
+		{name: "invalid config", cfg: WatcherConfig{Name: "a"}, err: "threshold must be greater than 0"},
+		{name: "other modes", cfg: WatcherConfig{Name: "a", Mode: ModeListings}, err: "only the cheapest mode"},
+		{name: "other currencies", cfg: WatcherConfig{Name: "a", Threshold: 1, BuyTokenType: handlers.TokenTypeERC20}, err: "only ETH listings"},
+		{name: "metadata filters", cfg: WatcherConfig{Name: "a", Threshold: 1, Metadata: map[string][]string{"Element": {"Fire"}}}, err: "metadata filters cannot be backtested"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Backtest(tt.cfg, history.NewMemoryStore(history.DefaultRetention), time.Now().Add(-time.Hour))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/config"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultWatchersFile = "watchers.json"
+
+	// ModeCheapest notifies when the cheapest listing is under the threshold.
+	ModeCheapest = "cheapest"
+	// ModeListings notifies about every new listing, optionally only those
+	// under the threshold.
+	ModeListings = "listings"
+	// ModeSales announces every completed sale. The threshold is ignored.
+	ModeSales = "sales"
+	// ModePriceDrops notifies when a token is relisted for less than its
+	// previous listing, optionally only when the new price is under the
+	// threshold.
+	ModePriceDrops = "price-drops"
+	// ModeUnderpriced notifies when a new listing is at least the minimum
+	// discount below the median price of recent sales and listings of tokens
+	// with the same rarity and level.
+	ModeUnderpriced = "underpriced"
+
+	// BuyTokenTypeAll matches orders in every currency. Only sales watchers
+	// support it, and it is their default.
+	BuyTokenTypeAll = "all"
+)
+
+// WatcherConfig is a single watcher definition from the watchers file.
+type WatcherConfig struct {
+	Name           string              `json:"name"`
+	Mode           string              `json:"mode,omitempty"`
+	Collection     string              `json:"collection"`
+	Rarity         []string            `json:"rarity"`
+	Metadata       map[string][]string `json:"metadata,omitempty"`
+	Threshold      float64             `json:"threshold"`
+	MinDropPct     float64             `json:"min_drop_percent,omitempty"`
+	MinDiscountPct float64             `json:"min_discount_percent,omitempty"`
+	Currency       coinbase.FiatSymbol `json:"currency,omitempty"`
+	BuyTokenType   string              `json:"buy_token_type,omitempty"`
+	Users          []string            `json:"users,omitempty"`
+	Channels       []string            `json:"channels,omitempty"`
+
+	// Set on watchers created at runtime with /watch.
+	GuildID string `json:"guild_id,omitempty"`
+	OwnerID string `json:"owner_id,omitempty"`
+}
+
+type WatchersFile struct {
+	Watchers []WatcherConfig `json:"watchers"`
+}
+
+// DefaultWatcherConfigs are used when no watchers file exists.
+var DefaultWatcherConfigs = []WatcherConfig{
+	{Name: "common", Collection: data.CollectionHero, Rarity: []string{"Common"}, Threshold: 250},
+	{Name: "rare", Collection: data.CollectionHero, Rarity: []string{"Rare"}, Threshold: 550},
+	{Name: "epic-legendary-mythic", Collection: data.CollectionHero, Rarity: []string{"Epic", "Legendary", "Mythic"}, Threshold: 800},
+}
+
+// LoadWatcherConfigs reads the watchers file named by the WATCHERS_FILE env
+// var (default watchers.json next to the executable), falling back to
+// DefaultWatcherConfigs when it does not exist.
+func LoadWatcherConfigs() ([]WatcherConfig, error) {
+	filename := config.GetenvStr("WATCHERS_FILE")
+	if filename == "" {
+		filename = DefaultWatchersFile
+	}
+
+	path := config.FilePath(filename)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Warnf("watchers file %v not found, using default watchers", path)
+		return ValidateWatcherConfigs(DefaultWatcherConfigs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file WatchersFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("could not parse watchers file %v: %w", path, err)
+	}
+
+	log.Infof("loaded %v watchers from %v", len(file.Watchers), path)
+	return ValidateWatcherConfigs(file.Watchers)
+}
+
+// ValidateWatcherConfigs checks every config and returns copies with defaults
+// filled in.
+func ValidateWatcherConfigs(cfgs []WatcherConfig) ([]WatcherConfig, error) {
+	names := make(map[string]bool, len(cfgs))
+	result := make([]WatcherConfig, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("watcher %d (%v): %w", i, cfg.Name, err)
+		}
+
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("watcher %d: duplicate name %v", i, cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		result = append(result, cfg)
+	}
+
+	return result, nil
+}
+
+// Validate checks the config and fills in defaults for optional fields.
+func (cfg *WatcherConfig) Validate() error {
+	if cfg.Collection == "" {
+		cfg.Collection = data.CollectionHero
+	}
+	col, ok := data.BitVerseCollections[cfg.Collection]
+	if !ok {
+		return fmt.Errorf("unknown collection %v", cfg.Collection)
+	}
+
+	for _, r := range cfg.Rarity {
+		if !col.IsRarity(r) {
+			return fmt.Errorf("unknown rarity %v for %v", r, col.Name)
+		}
+	}
+
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = ModeCheapest
+	case ModeCheapest, ModeListings, ModeSales, ModePriceDrops, ModeUnderpriced:
+	default:
+		return fmt.Errorf("unknown mode %v", cfg.Mode)
+	}
+
+	if cfg.Mode == ModeSales {
+		cfg.Threshold = 0
+	} else if cfg.Threshold < 0 || (cfg.Threshold == 0 && cfg.Mode == ModeCheapest) {
+		return fmt.Errorf("threshold must be greater than 0")
+	}
+
+	if cfg.MinDropPct < 0 || cfg.MinDropPct >= 100 {
+		return fmt.Errorf("min drop percent must be between 0 and 100")
+	}
+
+	if cfg.Mode == ModeUnderpriced && (cfg.MinDiscountPct <= 0 || cfg.MinDiscountPct >= 100) {
+		return fmt.Errorf("min discount percent must be between 0 and 100")
+	}
+
+	switch cfg.Currency {
+	case "":
+		cfg.Currency = coinbase.FiatUSD
+	case coinbase.FiatUSD, coinbase.FiatEUR, coinbase.FiatGBP:
+	default:
+		return fmt.Errorf("unknown currency %v", cfg.Currency)
+	}
+
+	switch cfg.BuyTokenType {
+	case "":
+		cfg.BuyTokenType = handlers.TokenTypeETH
+		if cfg.Mode == ModeSales {
+			cfg.BuyTokenType = BuyTokenTypeAll
+		}
+	case handlers.TokenTypeETH, handlers.TokenTypeERC20:
+	case BuyTokenTypeAll:
+		if cfg.Mode != ModeSales {
+			return fmt.Errorf("buy token type %v is only supported in %v mode", BuyTokenTypeAll, ModeSales)
+		}
+	default:
+		return fmt.Errorf("unknown buy token type %v", cfg.BuyTokenType)
+	}
+
+	if cfg.Name == "" {
+		cfg.Name = fmt.Sprintf("%s-%s-%v", cfg.Collection, strings.Join(cfg.Rarity, "-"), cfg.Threshold)
+	}
+
+	return nil
+}
+
+// sellMetadata merges the rarity list into the metadata filters.
+func (cfg WatcherConfig) sellMetadata() map[string][]string {
+	metadata := make(map[string][]string, len(cfg.Metadata)+1)
+	for k, v := range cfg.Metadata {
+		metadata[k] = v
+	}
+
+	if len(cfg.Rarity) > 0 {
+		metadata[cfg.collection().RarityKey()] = cfg.Rarity
+	}
+
+	return metadata
+}
+
+// buyTokenType is the orders API filter, which is empty for every currency.
+func (cfg WatcherConfig) buyTokenType() string {
+	if cfg.BuyTokenType == BuyTokenTypeAll {
+		return ""
+	}
+
+	return cfg.BuyTokenType
+}
+
+func (cfg WatcherConfig) collection() data.BitVerseCollection {
+	return data.BitVerseCollections[cfg.Collection]
+}
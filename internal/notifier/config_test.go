@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// addTestCollection registers a gems collection with its own rarities until
+// the test ends.
+func addTestCollection(t *testing.T) {
+	registry, keys := data.BitVerseCollections, data.CollectionKeys
+	t.Cleanup(func() {
+		data.BitVerseCollections, data.CollectionKeys = registry, keys
+	})
+
+	data.BitVerseCollections = make(map[string]data.BitVerseCollection, len(registry)+1)
+	for k, col := range registry {
+		data.BitVerseCollections[k] = col
+	}
+	data.BitVerseCollections["gems"] = data.BitVerseCollection{
+		Key:      "gems",
+		Name:     "Gems",
+		Address:  "0x3333333333333333333333333333333333333333",
+		Rarities: []string{"Dull", "Shiny"},
+	}
+	data.CollectionKeys = append(append([]string{}, keys...), "gems")
+}
+
+func TestValidateWatcherConfigs(t *testing.T) {
+	addTestCollection(t)
+
+	tests := []struct {
+		name string
+		cfgs []WatcherConfig
+		err  string
+	}{
+		{name: "defaults", cfgs: DefaultWatcherConfigs},
+		{name: "every mode", cfgs: []WatcherConfig{
+			{Name: "cheapest", Threshold: 100},
+			{Name: "listings", Mode: ModeListings},
+			{Name: "sales", Mode: ModeSales},
+			{Name: "price-drops", Mode: ModePriceDrops, MinDropPct: 20},
+			{Name: "underpriced", Mode: ModeUnderpriced, MinDiscountPct: 30},
+		}},
+		{name: "unknown collection", cfgs: []WatcherConfig{{Name: "a", Collection: "dragons", Threshold: 1}}, err: "unknown collection dragons"},
+		{name: "unknown rarity", cfgs: []WatcherConfig{{Name: "a", Rarity: []string{"Common", "Shiny"}, Threshold: 1}}, err: "unknown rarity Shiny"},
+		{name: "rarity of the collection", cfgs: []WatcherConfig{{Name: "a", Collection: "gems", Rarity: []string{"Shiny"}, Threshold: 1}}},
+		{name: "rarity of another collection", cfgs: []WatcherConfig{{Name: "a", Collection: "gems", Rarity: []string{"Common"}, Threshold: 1}}, err: "unknown rarity Common for Gems"},
+		{name: "unknown mode", cfgs: []WatcherConfig{{Name: "a", Mode: "cheap", Threshold: 1}}, err: "unknown mode cheap"},
+		{name: "cheapest without threshold", cfgs: []WatcherConfig{{Name: "a"}}, err: "threshold must be greater than 0"},
+		{name: "negative threshold", cfgs: []WatcherConfig{{Name: "a", Mode: ModeListings, Threshold: -5}}, err: "threshold must be greater than 0"},
+		{name: "drop of 100 percent", cfgs: []WatcherConfig{{Name: "a", Mode: ModePriceDrops, MinDropPct: 100}}, err: "min drop percent"},
+		{name: "underpriced without discount", cfgs: []WatcherConfig{{Name: "a", Mode: ModeUnderpriced}}, err: "min discount percent"},
+		{name: "unknown currency", cfgs: []WatcherConfig{{Name: "a", Threshold: 1, Currency: "JPY"}}, err: "unknown currency JPY"},
+		{name: "unknown buy token type", cfgs: []WatcherConfig{{Name: "a", Threshold: 1, BuyTokenType: "BTC"}}, err: "unknown buy token type BTC"},
+		{name: "every currency outside sales", cfgs: []WatcherConfig{{Name: "a", Threshold: 1, BuyTokenType: BuyTokenTypeAll}}, err: "only supported in sales mode"},
+		{name: "duplicate names", cfgs: []WatcherConfig{{Name: "a", Threshold: 1}, {Name: "b", Threshold: 1}, {Name: "a", Threshold: 2}}, err: "watcher 2: duplicate name a"},
+		{name: "error names the watcher", cfgs: []WatcherConfig{{Name: "a", Threshold: 1}, {Name: "b"}}, err: "watcher 1 (b)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateWatcherConfigs(tt.cfgs)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(tt.cfgs) {
+					t.Errorf("got %v configs, want %v", len(got), len(tt.cfgs))
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateFillsDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  WatcherConfig
+		want WatcherConfig
+	}{
+		{
+			name: "cheapest",
+			cfg:  WatcherConfig{Rarity: []string{"Rare"}, Threshold: 300},
+			want: WatcherConfig{
+				Name:         "hero-Rare-300",
+				Mode:         ModeCheapest,
+				Collection:   data.CollectionHero,
+				Rarity:       []string{"Rare"},
+				Threshold:    300,
+				Currency:     coinbase.FiatUSD,
+				BuyTokenType: handlers.TokenTypeETH,
+			},
+		},
+		{
+			name: "sales ignore the threshold and match every currency",
+			cfg:  WatcherConfig{Name: "sales", Mode: ModeSales, Collection: data.CollectionPortal, Threshold: 50, Currency: coinbase.FiatGBP},
+			want: WatcherConfig{
+				Name:         "sales",
+				Mode:         ModeSales,
+				Collection:   data.CollectionPortal,
+				Currency:     coinbase.FiatGBP,
+				BuyTokenType: BuyTokenTypeAll,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if err := cfg.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Name != tt.want.Name || cfg.Mode != tt.want.Mode || cfg.Collection != tt.want.Collection ||
+				cfg.Threshold != tt.want.Threshold || cfg.Currency != tt.want.Currency || cfg.BuyTokenType != tt.want.BuyTokenType {
+				t.Errorf("got %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWatcherConfigsKeepsInput(t *testing.T) {
+	cfgs := []WatcherConfig{{Name: "a", Threshold: 1}}
+	if _, err := ValidateWatcherConfigs(cfgs); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfgs[0].Mode != "" || cfgs[0].Currency != "" {
+		t.Errorf("defaults were written to the input: %+v", cfgs[0])
+	}
+}
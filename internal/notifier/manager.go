@@ -0,0 +1,344 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/jsonfile"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	MaxWatchersPerUser         = 5
+	DefaultRuntimeWatchersFile = "runtime_watchers.json"
+)
+
+var (
+	ErrWatcherNotFound = errors.New("watcher not found")
+	ErrNotWatcherOwner = errors.New("watcher is owned by another user")
+	ErrTooManyWatchers = fmt.Errorf("users may create at most %v watchers", MaxWatchersPerUser)
+)
+
+// Manager owns every running watcher, both the ones from the watchers file
+// and the ones created at runtime by guild members.
+type Manager struct {
+	clients  *api.ClientsManager
+	index    *index.Index
+	path     string
+	seen     SeenStore
+	session  discord.Transport
+	subs     *Subscriptions
+	watchers map[string]*Watcher
+	nextID   int
+	mu       sync.Mutex
+}
+
+// runtimeWatchersFile is where runtime watchers are saved so they, and their
+// IDs, survive restarts. IDs are never reused, otherwise a new watcher would
+// inherit the subscribers and seen listings of a deleted one.
+type runtimeWatchersFile struct {
+	NextID   int              `json:"next_id"`
+	Watchers []runtimeWatcher `json:"watchers"`
+}
+
+type runtimeWatcher struct {
+	WatcherConfig
+	Paused bool `json:"paused,omitempty"`
+}
+
+// NewManager creates a manager that saves runtime watchers to path, or keeps
+// them in memory only when path is empty.
+func NewManager(
+	cm *api.ClientsManager,
+	session discord.Transport,
+	seen SeenStore,
+	subs *Subscriptions,
+	idx *index.Index,
+	path string,
+) *Manager {
+	return &Manager{
+		clients:  cm,
+		index:    idx,
+		path:     path,
+		seen:     seen,
+		session:  session,
+		subs:     subs,
+		watchers: make(map[string]*Watcher),
+		nextID:   1,
+	}
+}
+
+// Load restores and starts the runtime watchers saved by a previous run.
+func (m *Manager) Load() error {
+	if m.path == "" {
+		return nil
+	}
+
+	var file runtimeWatchersFile
+	if err := jsonfile.Load(m.path, &file); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if file.NextID > m.nextID {
+		m.nextID = file.NextID
+	}
+
+	var start []*Watcher
+	for _, rw := range file.Watchers {
+		cfg := rw.WatcherConfig
+		if err := cfg.Validate(); err != nil {
+			log.Warnf("skipping saved watcher %v: %v", cfg.Name, err)
+			continue
+		}
+
+		if _, ok := m.watchers[cfg.Name]; ok {
+			log.Warnf("skipping saved watcher %v: the name is already in use", cfg.Name)
+			continue
+		}
+
+		w := NewWatcher(m.clients, m.session, cfg, m.seen, m.subs, m.index)
+		m.watchers[cfg.Name] = w
+		if !rw.Paused {
+			start = append(start, w)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, w := range start {
+		if err := w.Start(); err != nil {
+			log.Errorf("could not start saved watcher %v: %v", w, err)
+		}
+	}
+
+	log.Infof("loaded %v runtime watchers from %v", len(file.Watchers), m.path)
+	return nil
+}
+
+// Add validates and starts a watcher. Runtime watchers (those with an owner)
+// are given a numeric ID as their name.
+func (m *Manager) Add(cfg WatcherConfig) (*Watcher, error) {
+	w, err := m.add(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.config.OwnerID != "" {
+		// Only possible when the runtime watchers file was lost, but never
+		// let a new watcher notify an old one's subscribers.
+		if err := m.subs.Clear(w.config.Name); err != nil {
+			log.Errorf("could not clear subscriptions of new watcher %v: %v", w, err)
+		}
+		if err := m.seen.Clear(w.config.Name); err != nil {
+			log.Errorf("could not clear seen listings of new watcher %v: %v", w, err)
+		}
+	}
+
+	// Started outside the lock so a slow start never blocks other commands.
+	if err := w.Start(); err != nil {
+		m.mu.Lock()
+		delete(m.watchers, w.config.Name)
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.save()
+	m.mu.Unlock()
+
+	log.Infof("added watcher %v", w)
+	return w, nil
+}
+
+// add reserves the name of a new watcher without starting it.
+func (m *Manager) add(cfg WatcherConfig) (*Watcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cfg.OwnerID != "" {
+		if len(m.ownedBy(cfg.OwnerID)) >= MaxWatchersPerUser {
+			return nil, ErrTooManyWatchers
+		}
+
+		for {
+			cfg.Name = strconv.Itoa(m.nextID)
+			m.nextID++
+			if _, ok := m.watchers[cfg.Name]; !ok {
+				break
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := m.watchers[cfg.Name]; ok {
+		return nil, fmt.Errorf("a watcher named %v already exists", cfg.Name)
+	}
+
+	w := NewWatcher(m.clients, m.session, cfg, m.seen, m.subs, m.index)
+	m.watchers[cfg.Name] = w
+	return w, nil
+}
+
+// List returns the watchers visible in the guild: the global ones from the
+// watchers file sorted by name, followed by the guild's own sorted by ID.
+func (m *Manager) List(guildID string) []*Watcher {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*Watcher
+	for _, w := range m.watchers {
+		if w.visibleIn(guildID) {
+			result = append(result, w)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].config, result[j].config
+		if (a.OwnerID == "") != (b.OwnerID == "") {
+			return a.OwnerID == ""
+		}
+
+		if a.OwnerID == "" {
+			return a.Name < b.Name
+		}
+
+		x, _ := strconv.Atoi(a.Name)
+		y, _ := strconv.Atoi(b.Name)
+		return x < y
+	})
+
+	return result
+}
+
+// Lookup finds a watcher by name if it is visible in the guild.
+func (m *Manager) Lookup(name, guildID string) (*Watcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, ok := m.watchers[name]
+	if !ok || !w.visibleIn(guildID) {
+		return nil, ErrWatcherNotFound
+	}
+
+	return w, nil
+}
+
+func (m *Manager) Remove(name, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, err := m.owned(name, userID)
+	if err != nil {
+		return err
+	}
+
+	w.Stop()
+	delete(m.watchers, name)
+	m.save()
+	log.Infof("removed watcher %v", w)
+
+	if err := m.seen.Clear(name); err != nil {
+		log.Errorf("could not clear seen listings of watcher %v: %v", w, err)
+	}
+
+	return m.subs.Clear(name)
+}
+
+func (m *Manager) Pause(name, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, err := m.owned(name, userID)
+	if err != nil {
+		return err
+	}
+
+	w.Stop()
+	m.save()
+	return nil
+}
+
+func (m *Manager) Resume(name, userID string) error {
+	m.mu.Lock()
+	w, err := m.owned(name, userID)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := w.Start(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.save()
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, w := range m.watchers {
+		w.Stop()
+	}
+}
+
+// save writes the runtime watchers to disk and must be called with mu held.
+// Failures are only logged since the watchers keep running either way.
+func (m *Manager) save() {
+	if m.path == "" {
+		return
+	}
+
+	file := runtimeWatchersFile{NextID: m.nextID, Watchers: []runtimeWatcher{}}
+	for _, w := range m.watchers {
+		if w.config.OwnerID != "" {
+			file.Watchers = append(file.Watchers, runtimeWatcher{WatcherConfig: w.config, Paused: !w.Started()})
+		}
+	}
+
+	sort.Slice(file.Watchers, func(i, j int) bool {
+		x, _ := strconv.Atoi(file.Watchers[i].Name)
+		y, _ := strconv.Atoi(file.Watchers[j].Name)
+		return x < y
+	})
+
+	if err := jsonfile.Save(m.path, file); err != nil {
+		log.Errorf("could not save runtime watchers to %v: %v", m.path, err)
+	}
+}
+
+func (m *Manager) owned(name, userID string) (*Watcher, error) {
+	w, ok := m.watchers[name]
+	if !ok || w.config.OwnerID == "" {
+		return nil, ErrWatcherNotFound
+	}
+
+	if w.config.OwnerID != userID {
+		return nil, ErrNotWatcherOwner
+	}
+
+	return w, nil
+}
+
+func (m *Manager) ownedBy(userID string) []*Watcher {
+	var result []*Watcher
+	for _, w := range m.watchers {
+		if w.config.OwnerID == userID {
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
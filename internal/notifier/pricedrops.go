@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	MaxBaselinePages = 100
+	// PriceBaselineRefresh is how often the active listings are reloaded so
+	// that sold and delisted tokens are forgotten.
+	PriceBaselineRefresh = time.Hour
+	PriceDropTemplate    = `Price dropped from %v to %v (-%0.1f%%):
+- name: %v
+- price: %v
+- rarity: %v
+- token id: %v
+- immutascan: %v
+- immutable market: %v`
+)
+
+// listedPrice is the last known listing price of a token.
+type listedPrice struct {
+	Price  float64
+	Symbol coinbase.CryptoSymbol
+	At     time.Time
+}
+
+// priceBaseline is the price of every active listing when the load started.
+type priceBaseline struct {
+	prices  map[string]listedPrice
+	started time.Time
+}
+
+// refreshPrices swaps in a finished baseline and starts loading a new one
+// every PriceBaselineRefresh. Loading can take a hundred pages, so it runs in
+// the background instead of delaying the checks or /watch add. Only the check
+// goroutine touches w.prices.
+func (w *Watcher) refreshPrices(cfg *orders.ListOrdersConfig) {
+	select {
+	case b := <-w.baseline:
+		// Prices seen by the checks while loading are newer than the
+		// baseline's, everything else not in the baseline is no longer listed.
+		for id, p := range w.prices {
+			if p.At.After(b.started) {
+				b.prices[id] = p
+			}
+		}
+
+		w.prices = b.prices
+		w.pricesAt = b.started
+		w.baseline = nil
+		log.Infof("tracking prices of %v active listings for %v", len(w.prices), w)
+	default:
+	}
+
+	if w.prices == nil {
+		w.prices = make(map[string]listedPrice)
+	}
+
+	if w.baseline == nil && time.Since(w.pricesAt) > PriceBaselineRefresh {
+		ch := make(chan priceBaseline, 1)
+		w.baseline = ch
+
+		baseline := *cfg
+		go func() { ch <- w.loadPrices(baseline) }()
+	}
+}
+
+// loadPrices records the price of every active listing so that relists can
+// be compared against something.
+func (w *Watcher) loadPrices(baseline orders.ListOrdersConfig) priceBaseline {
+	baseline.OrderBy = "created_at"
+	baseline.UpdatedMinTimestamp = ""
+
+	b := priceBaseline{prices: make(map[string]listedPrice), started: time.Now()}
+	var since time.Time
+	for page := 0; page < MaxBaselinePages; page++ {
+		baseline.MinTimestamp = since.UTC().Format(time.RFC3339)
+		result, err := w.clients.OrdersClient.ListOrders(context.Background(), &baseline)
+		if err != nil {
+			log.Errorf("could not load active listings for %v: %v", w, err)
+			return b
+		}
+
+		for _, order := range result {
+			if t, err := time.Parse(time.RFC3339, order.GetTimestamp()); err == nil && t.After(since) {
+				since = t
+			}
+
+			b.prices[order.Sell.Data.GetTokenId()] = listedPrice{
+				Price:  w.getPrice(order),
+				Symbol: w.getCryptoSymbol(order.GetBuy().Type),
+				At:     b.started,
+			}
+		}
+
+		if len(result) < baseline.PageSize {
+			return b
+		}
+	}
+
+	log.Warnf("watcher %v reached the %v page limit loading active listings", w, MaxBaselinePages)
+	return b
+}
+
+// checkPriceDrops compares every listing created or updated since the
+// previous poll with the token's last known price.
+func (w *Watcher) checkPriceDrops(cfg *orders.ListOrdersConfig) {
+	w.refreshPrices(cfg)
+
+	w.eachNewOrder(cfg, func(order imxapi.Order, l listing) {
+		prev, ok := w.prices[l.TokenID]
+		w.prices[l.TokenID] = listedPrice{Price: l.CryptoPrice, Symbol: l.CryptoSymbol, At: time.Now()}
+
+		if !ok || prev.Symbol != l.CryptoSymbol || prev.Price <= 0 || l.CryptoPrice >= prev.Price {
+			return
+		}
+
+		drop := (prev.Price - l.CryptoPrice) / prev.Price * 100
+		if drop < w.config.MinDropPct {
+			log.Debugf("price of #%v dropped %0.1f%%, under the %v%% minimum", l.TokenID, drop, w.config.MinDropPct)
+			return
+		}
+
+		if w.config.Threshold > 0 && l.FiatPrice > w.config.Threshold {
+			return
+		}
+
+		if len(w.config.Rarity) != 1 {
+			if asset, err := w.getAsset(order); err == nil {
+				l.Rarity = getMetadataString(asset.GetMetadata(), w.config.collection().RarityKey())
+			}
+		}
+
+		from := fmt.Sprintf("%f %s", prev.Price, prev.Symbol)
+		to := fmt.Sprintf("%f %s", l.CryptoPrice, l.CryptoSymbol)
+		log.Infof("price of #%v dropped from %v to %v (-%0.1f%%)", l.TokenID, from, to, drop)
+		w.notify(l, &discordgo.MessageSend{
+			Content: fmt.Sprintf(PriceDropTemplate, from, to, drop, l.Name, l.FiatPriceStr, l.Rarity, l.TokenID, l.URLs.Immutascan, l.URLs.ImmutableMarket),
+		})
+	})
+}
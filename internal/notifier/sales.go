@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// checkSales announces every order filled since the previous poll.
+func (w *Watcher) checkSales(cfg *orders.ListOrdersConfig) {
+	w.eachNewOrder(cfg, func(order imxapi.Order, l listing) {
+		log.Infof("new sale %v (#%v) for %v (%v %v)", order.OrderId, l.TokenID, l.FiatPriceStr, l.CryptoPrice, l.CryptoSymbol)
+		w.notify(l, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{w.getSaleEmbed(order, l)},
+		})
+	})
+}
+
+func (w *Watcher) getSaleEmbed(order imxapi.Order, l listing) *discordgo.MessageEmbed {
+	heroName := "(Unknown)"
+	owner := "(Unknown)"
+
+	// Orders only record the seller. The buyer is not available, so show the
+	// owner at poll time, which differs if the token moved on since the sale.
+	if asset, err := w.getAsset(order); err == nil {
+		metadata := asset.GetMetadata()
+		heroName = getMetadataString(metadata, w.config.collection().HeroNameKey())
+		l.Rarity = getMetadataString(metadata, w.config.collection().RarityKey())
+		owner = handlers.GetImmutascanUserURL(asset.GetUser())
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Price", Value: fmt.Sprintf("%f %s / %s", l.CryptoPrice, l.CryptoSymbol, l.FiatPriceStr)},
+		{Name: "Rarity", Value: l.Rarity},
+		{Name: "Token ID", Value: l.TokenID},
+		{Name: "Seller", Value: handlers.GetImmutascanUserURL(order.GetUser())},
+		{Name: "Current Owner", Value: owner},
+		{Name: "Record of Sale", Value: handlers.GetImmutascanOrderURL(order.OrderId)},
+	}
+
+	if w.config.Collection == data.CollectionHero {
+		fields = append([]*discordgo.MessageEmbedField{{Name: "Hero Name", Value: heroName}}, fields...)
+	}
+
+	sell := order.Sell.GetData()
+	return &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("Sold: %s", l.Name),
+		URL:       l.URLs.Immutascan,
+		Fields:    fields,
+		Timestamp: order.GetUpdatedTimestamp(),
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: sell.Properties.GetImageUrl()},
+	}
+}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultSampleInterval = 15 * time.Minute
+
+// Sampler periodically records the ETH floor of every rarity in every
+// collection into the price history.
+type Sampler struct {
+	interval time.Duration
+	orders   *handlers.OrdersHandler
+	spot     api.SpotPriceClient
+	store    history.Store
+	stop     chan struct{}
+}
+
+// NewSampler creates a sampler, using DefaultSampleInterval when interval is
+// not positive.
+func NewSampler(cm *api.ClientsManager, store history.Store, interval time.Duration) *Sampler {
+	if interval <= 0 {
+		log.Warnf("invalid sample interval %v, using %v", interval, DefaultSampleInterval)
+		interval = DefaultSampleInterval
+	}
+
+	return &Sampler{
+		interval: interval,
+		orders:   handlers.NewOrdersHandler(cm, nil),
+		spot:     cm.SpotPriceClient,
+		store:    store,
+	}
+}
+
+func (s *Sampler) Start() {
+	log.Infof("starting floor price sampler every %v", s.interval)
+	s.stop = make(chan struct{}, 1)
+
+	// The first sample runs in the background too, so startup never waits on
+	// the network.
+	go func() {
+		s.sample()
+
+		ticker := time.NewTicker(s.interval)
+		for {
+			select {
+			case <-s.stop:
+				log.Info("received stop in floor price sampler")
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+}
+
+func (s *Sampler) Stop() {
+	log.Info("stopping floor price sampler")
+	close(s.stop)
+}
+
+func (s *Sampler) sample() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	for key, col := range data.BitVerseCollections {
+		floors, err := s.orders.GetFloors(ctx, col, handlers.TokenTypeETH)
+		if err != nil {
+			log.Errorf("could not sample %v floors: %v", col.Name, err)
+			continue
+		}
+
+		for _, floor := range floors {
+			if floor.Order == nil {
+				continue
+			}
+
+			sample := history.Sample{
+				At:         now,
+				Collection: key,
+				Rarity:     floor.Rarity,
+				TokenID:    floor.Order.Sell.Data.GetTokenId(),
+				Price:      floor.CryptoPrice,
+				Symbol:     floor.CryptoSymbol,
+				Fiat:       make(map[coinbase.FiatSymbol]float64),
+			}
+
+			for _, fiat := range []coinbase.FiatSymbol{coinbase.FiatUSD, coinbase.FiatEUR, coinbase.FiatGBP} {
+				sample.Fiat[fiat] = floor.CryptoPrice * s.spot.RetrieveSpotPrice(floor.CryptoSymbol, fiat)
+			}
+
+			if err := s.store.Add(sample); err != nil {
+				log.Errorf("could not record %v %v floor: %v", key, floor.Rarity, err)
+			}
+		}
+	}
+
+	log.Debug("recorded floor price samples")
+}
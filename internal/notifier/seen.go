@@ -0,0 +1,199 @@
+package notifier
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/jsonfile"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultSeenFile = "seen.json"
+	DefaultSeenTTL  = 7 * 24 * time.Hour
+)
+
+type Seen struct {
+	ID    string    `json:"id"`
+	Price float64   `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// SeenStore remembers which listings each watcher has already notified about.
+type SeenStore interface {
+	AlreadySeen(watcher, id string, price float64) bool
+	Add(watcher, id string, price float64) error
+	// Clear forgets everything the watcher has seen.
+	Clear(watcher string) error
+	// Flush persists the listings added since the last flush, if the store
+	// is persistent. Watchers flush once per check.
+	Flush() error
+}
+
+// MemorySeenStore keeps seen listings in memory only, so they are forgotten
+// on restart. Each watcher keeps the latest price seen per listing, and
+// expired listings are dropped on Flush.
+type MemorySeenStore struct {
+	seens map[string]map[string]Seen
+	ttl   time.Duration
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+func NewMemorySeenStore(ttl time.Duration) *MemorySeenStore {
+	return &MemorySeenStore{seens: make(map[string]map[string]Seen), ttl: ttl, now: time.Now}
+}
+
+func (s *MemorySeenStore) AlreadySeen(watcher, id string, price float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen, ok := s.seens[watcher][id]
+	return ok && seen.Price == price && !s.expired(seen)
+}
+
+func (s *MemorySeenStore) Add(watcher, id string, price float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(watcher, id, price)
+	return nil
+}
+
+func (s *MemorySeenStore) Clear(watcher string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.seens, watcher)
+	return nil
+}
+
+func (s *MemorySeenStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	return nil
+}
+
+func (s *MemorySeenStore) add(watcher, id string, price float64) {
+	if s.seens[watcher] == nil {
+		s.seens[watcher] = make(map[string]Seen)
+	}
+
+	s.seens[watcher][id] = Seen{ID: id, Price: price, At: s.now()}
+}
+
+// prune drops the expired listings and reports whether there were any.
+func (s *MemorySeenStore) prune() bool {
+	var pruned bool
+	for watcher, seens := range s.seens {
+		for id, seen := range seens {
+			if s.expired(seen) {
+				delete(seens, id)
+				pruned = true
+			}
+		}
+
+		if len(seens) == 0 {
+			delete(s.seens, watcher)
+		}
+	}
+
+	return pruned
+}
+
+func (s *MemorySeenStore) expired(seen Seen) bool {
+	return s.ttl > 0 && s.now().Sub(seen.At) > s.ttl
+}
+
+// FileSeenStore is a MemorySeenStore that is loaded from and saved to a JSON
+// file so restarts do not re-send notifications. Added listings are only
+// written on Flush, so a poll that sees hundreds of orders saves once.
+type FileSeenStore struct {
+	*MemorySeenStore
+	dirty bool
+	path  string
+}
+
+func NewFileSeenStore(path string, ttl time.Duration) (*FileSeenStore, error) {
+	s := &FileSeenStore{MemorySeenStore: NewMemorySeenStore(ttl), path: path}
+
+	var file map[string][]Seen
+	if err := jsonfile.Load(path, &file); err != nil {
+		return nil, err
+	}
+
+	for watcher, seens := range file {
+		s.seens[watcher] = make(map[string]Seen, len(seens))
+		for _, seen := range seens {
+			if prev, ok := s.seens[watcher][seen.ID]; !ok || seen.At.After(prev.At) {
+				s.seens[watcher][seen.ID] = seen
+			}
+		}
+	}
+
+	log.Infof("loaded seen listings for %v watchers from %v", len(s.seens), path)
+	return s, nil
+}
+
+func (s *FileSeenStore) Add(watcher, id string, price float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(watcher, id, price)
+	s.dirty = true
+	return nil
+}
+
+func (s *FileSeenStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pruned := s.prune(); !pruned && !s.dirty {
+		return nil
+	}
+
+	return s.save()
+}
+
+func (s *FileSeenStore) Clear(watcher string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seens[watcher]; !ok {
+		return nil
+	}
+
+	delete(s.seens, watcher)
+	return s.save()
+}
+
+// save writes the listings of each watcher oldest first. It must be called
+// with mu held.
+func (s *FileSeenStore) save() error {
+	file := make(map[string][]Seen, len(s.seens))
+	for watcher, seens := range s.seens {
+		list := make([]Seen, 0, len(seens))
+		for _, seen := range seens {
+			list = append(list, seen)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].At.Equal(list[j].At) {
+				return list[i].At.Before(list[j].At)
+			}
+
+			return list[i].ID < list[j].ID
+		})
+
+		file[watcher] = list
+	}
+
+	if err := jsonfile.Save(s.path, file); err != nil {
+		return err
+	}
+
+	s.dirty = false
+	return nil
+}
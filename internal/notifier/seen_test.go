@@ -0,0 +1,155 @@
+package notifier
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// clock is a settable time source for the seen stores.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func newTestClock() *clock {
+	return &clock{t: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestMemorySeenStore(t *testing.T) {
+	c := newTestClock()
+	s := NewMemorySeenStore(time.Hour)
+	s.now = c.now
+
+	if s.AlreadySeen("a", "1", 0.5) {
+		t.Error("empty store has seen 1")
+	}
+
+	s.Add("a", "1", 0.5)
+	tests := []struct {
+		watcher string
+		id      string
+		price   float64
+		want    bool
+	}{
+		{watcher: "a", id: "1", price: 0.5, want: true},
+		{watcher: "a", id: "1", price: 0.4, want: false},
+		{watcher: "a", id: "2", price: 0.5, want: false},
+		{watcher: "b", id: "1", price: 0.5, want: false},
+	}
+	for _, tt := range tests {
+		if got := s.AlreadySeen(tt.watcher, tt.id, tt.price); got != tt.want {
+			t.Errorf("AlreadySeen(%v, %v, %v) = %v, want %v", tt.watcher, tt.id, tt.price, got, tt.want)
+		}
+	}
+
+	// A relisting replaces the earlier price.
+	s.Add("a", "1", 0.4)
+	if !s.AlreadySeen("a", "1", 0.4) || s.AlreadySeen("a", "1", 0.5) {
+		t.Error("relisting did not replace the seen price")
+	}
+}
+
+func TestMemorySeenStoreExpiry(t *testing.T) {
+	c := newTestClock()
+	s := NewMemorySeenStore(time.Hour)
+	s.now = c.now
+
+	s.Add("a", "old", 1)
+	c.t = c.t.Add(45 * time.Minute)
+	s.Add("a", "new", 1)
+
+	c.t = c.t.Add(30 * time.Minute)
+	if s.AlreadySeen("a", "old", 1) {
+		t.Error("expired listing still seen")
+	}
+	if !s.AlreadySeen("a", "new", 1) {
+		t.Error("listing forgotten before its TTL")
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.seens["a"]["old"]; ok {
+		t.Error("expired listing kept after Flush")
+	}
+	if len(s.seens["a"]) != 1 {
+		t.Errorf("got %v listings after Flush, want 1", len(s.seens["a"]))
+	}
+
+	c.t = c.t.Add(time.Hour)
+	s.Flush()
+	if _, ok := s.seens["a"]; ok {
+		t.Error("watcher without listings kept after Flush")
+	}
+}
+
+func TestMemorySeenStoreClear(t *testing.T) {
+	s := NewMemorySeenStore(DefaultSeenTTL)
+	s.Add("a", "1", 1)
+	s.Add("b", "1", 1)
+
+	if err := s.Clear("a"); err != nil {
+		t.Fatal(err)
+	}
+	if s.AlreadySeen("a", "1", 1) {
+		t.Error("cleared watcher still has seen 1")
+	}
+	if !s.AlreadySeen("b", "1", 1) {
+		t.Error("clearing a forgot the listings of b")
+	}
+}
+
+func TestFileSeenStoreRoundTrip(t *testing.T) {
+	c := newTestClock()
+	path := filepath.Join(t.TempDir(), DefaultSeenFile)
+
+	s, err := NewFileSeenStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = c.now
+
+	s.Add("a", "1", 0.5)
+	s.Add("a", "2", 0.7)
+	s.Add("b", "1", 0.5)
+
+	// Nothing is written until Flush.
+	if loaded, err := NewFileSeenStore(path, time.Hour); err != nil || len(loaded.seens) != 0 {
+		t.Fatalf("got %v watchers before Flush (err %v), want none", len(loaded.seens), err)
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Clear("b"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewFileSeenStore(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.now = c.now
+
+	for _, id := range []string{"1", "2"} {
+		if got, want := loaded.seens["a"][id], s.seens["a"][id]; !got.At.Equal(want.At) || got.Price != want.Price {
+			t.Errorf("loaded %+v for %v, want %+v", got, id, want)
+		}
+	}
+	if loaded.AlreadySeen("b", "1", 0.5) {
+		t.Error("cleared watcher b was saved")
+	}
+
+	// Expired listings are pruned from the file on the next Flush.
+	c.t = c.t.Add(2 * time.Hour)
+	if err := loaded.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := NewFileSeenStore(path, time.Hour); err != nil || len(reloaded.seens) != 0 {
+		t.Errorf("got %v watchers after pruning (err %v), want none", len(reloaded.seens), err)
+	}
+}
@@ -2,32 +2,41 @@ package notifier
 
 import (
 	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
 )
 
 type SendingFunc func(str string) (*discordgo.Message, error)
 
 type DiscordSender struct {
-	session *discordgo.Session
+	session discord.Transport
 }
 
-func NewDiscordSender(session *discordgo.Session) *DiscordSender {
+func NewDiscordSender(session discord.Transport) *DiscordSender {
 	return &DiscordSender{
 		session: session,
 	}
 }
 
 func (s *DiscordSender) SendChannel(channelID, msg string) error {
-	_, err := s.session.ChannelMessageSend(channelID, msg)
+	return s.SendChannelMessage(channelID, &discordgo.MessageSend{Content: msg})
+}
+
+func (s *DiscordSender) SendChannelMessage(channelID string, msg *discordgo.MessageSend) error {
+	_, err := s.session.ChannelMessageSendComplex(channelID, msg)
 	return err
 }
 
 func (s *DiscordSender) SendDM(userID, msg string) error {
+	return s.SendDMMessage(userID, &discordgo.MessageSend{Content: msg})
+}
+
+func (s *DiscordSender) SendDMMessage(userID string, msg *discordgo.MessageSend) error {
 	dmChannel, err := s.session.UserChannelCreate(userID)
 	if err != nil {
 		return err
 	}
 
-	if _, err = s.session.ChannelMessageSend(dmChannel.ID, msg); err != nil {
+	if _, err = s.session.ChannelMessageSendComplex(dmChannel.ID, msg); err != nil {
 		return err
 	}
 
@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/config"
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/jsonfile"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultSubscriptionsFile = "subscriptions.json"
+
+type Recipients struct {
+	Users    []string `json:"users,omitempty"`
+	Channels []string `json:"channels,omitempty"`
+}
+
+// Subscriptions is the registry of users and channels that opted in to each
+// watcher. It is saved to disk on every change.
+type Subscriptions struct {
+	path string
+	subs map[string]*Recipients
+	mu   sync.Mutex
+}
+
+// NewSubscriptions loads the subscriptions saved at path. An empty path keeps
+// them in memory only.
+func NewSubscriptions(path string) (*Subscriptions, error) {
+	s := &Subscriptions{path: path, subs: make(map[string]*Recipients)}
+	if err := jsonfile.Load(path, &s.subs); err != nil {
+		return nil, err
+	}
+
+	log.Infof("loaded subscriptions for %v watchers from %v", len(s.subs), path)
+	return s, nil
+}
+
+// ImportEnv subscribes the legacy USER_SUBSCRIPTIONS and CHANNEL_SUBSCRIPTIONS
+// env lists to the given watchers, but only while the registry is still empty.
+func (s *Subscriptions) ImportEnv(watchers []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subs) > 0 {
+		return nil
+	}
+
+	users := splitIDs(config.GetenvStr("USER_SUBSCRIPTIONS"))
+	channels := splitIDs(config.GetenvStr("CHANNEL_SUBSCRIPTIONS"))
+	if len(users) == 0 && len(channels) == 0 {
+		return nil
+	}
+
+	for _, name := range watchers {
+		r := s.get(name)
+		for _, id := range users {
+			r.Users = appendUnique(r.Users, id)
+		}
+		for _, id := range channels {
+			r.Channels = appendUnique(r.Channels, id)
+		}
+	}
+
+	log.Infof("imported %v user and %v channel subscriptions from env", len(users), len(channels))
+	return jsonfile.Save(s.path, s.subs)
+}
+
+// Recipients returns a copy of the watcher's subscribers.
+func (s *Subscriptions) Recipients(watcher string) Recipients {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.subs[watcher]
+	if !ok {
+		return Recipients{}
+	}
+
+	return Recipients{
+		Users:    append([]string(nil), r.Users...),
+		Channels: append([]string(nil), r.Channels...),
+	}
+}
+
+func (s *Subscriptions) SubscribeUser(watcher, userID string) error {
+	return s.update(watcher, func(r *Recipients) { r.Users = appendUnique(r.Users, userID) })
+}
+
+func (s *Subscriptions) UnsubscribeUser(watcher, userID string) error {
+	return s.update(watcher, func(r *Recipients) { r.Users = remove(r.Users, userID) })
+}
+
+func (s *Subscriptions) SubscribeChannel(watcher, channelID string) error {
+	return s.update(watcher, func(r *Recipients) { r.Channels = appendUnique(r.Channels, channelID) })
+}
+
+func (s *Subscriptions) UnsubscribeChannel(watcher, channelID string) error {
+	return s.update(watcher, func(r *Recipients) { r.Channels = remove(r.Channels, channelID) })
+}
+
+// Clear drops every subscription to a deleted watcher.
+func (s *Subscriptions) Clear(watcher string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[watcher]; !ok {
+		return nil
+	}
+
+	delete(s.subs, watcher)
+	return jsonfile.Save(s.path, s.subs)
+}
+
+func (s *Subscriptions) update(watcher string, fn func(r *Recipients)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.get(watcher)
+	fn(r)
+	if len(r.Users) == 0 && len(r.Channels) == 0 {
+		delete(s.subs, watcher)
+	}
+
+	return jsonfile.Save(s.path, s.subs)
+}
+
+func (s *Subscriptions) get(watcher string) *Recipients {
+	r, ok := s.subs[watcher]
+	if !ok {
+		r = &Recipients{}
+		s.subs[watcher] = r
+	}
+
+	return r
+}
+
+func appendUnique(ids []string, id string) []string {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+
+	return append(ids, id)
+}
+
+func remove(ids []string, id string) []string {
+	var result []string
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+
+	return result
+}
+
+func splitIDs(str string) []string {
+	var ids []string
+	for _, id := range strings.Split(str, ",") {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
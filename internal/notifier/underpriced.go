@@ -0,0 +1,351 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FairValueWindow is how far back sales count towards fair values.
+	FairValueWindow = 7 * 24 * time.Hour
+	// FairValueRefresh is how often comparables are reloaded from scratch so
+	// that delisted tokens and old sales drop out.
+	FairValueRefresh = time.Hour
+	// MinComparables is the fewest prices a fair value is computed from. With
+	// fewer prices for the rarity and level, the whole rarity is used.
+	MinComparables = 5
+	// MaxComparables caps the prices kept per group, newest first.
+	MaxComparables = 50
+	// TraitsTTL is how long the traits of tokens outside the hero index are
+	// cached. Levels change, but rarely enough for a day to be fine.
+	TraitsTTL = 24 * time.Hour
+
+	UnderpricedTemplate = `Listed %0.1f%% below fair value:
+- name: %v
+- price: %v
+- fair value: %v (median of %v similar)
+- rarity: %v
+- level: %v
+- token id: %v
+- immutascan: %v
+- immutable market: %v`
+)
+
+// traits are the attributes that make tokens comparable.
+type traits struct {
+	Rarity string
+	Level  string
+}
+
+// comparable is one recent sale or listing price of a token.
+type comparable struct {
+	TokenID string
+	Price   float64
+	Symbol  coinbase.CryptoSymbol
+	At      time.Time
+	Traits  traits
+}
+
+// fairValues groups comparable prices by crypto symbol, rarity and level.
+type fairValues struct {
+	groups   map[string][]comparable
+	loadedAt time.Time
+}
+
+// traitsCache remembers the traits fetched from assets, since loading fair
+// values would otherwise fetch one asset per order on every refresh.
+type traitsCache struct {
+	entries map[string]cachedTraits
+	mu      sync.Mutex
+}
+
+type cachedTraits struct {
+	traits traits
+	at     time.Time
+}
+
+func newTraitsCache() *traitsCache {
+	return &traitsCache{entries: make(map[string]cachedTraits)}
+}
+
+func (c *traitsCache) get(key string) (traits, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.at) > TraitsTTL {
+		return traits{}, false
+	}
+
+	return e.traits, true
+}
+
+func (c *traitsCache) put(key string, t traits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cachedTraits{traits: t, at: time.Now()}
+}
+
+func fairValueKey(symbol coinbase.CryptoSymbol, rarity, level string) string {
+	return fmt.Sprintf("%s/%s/%s", symbol, rarity, level)
+}
+
+func (f *fairValues) add(c comparable) {
+	for _, key := range []string{
+		fairValueKey(c.Symbol, c.Traits.Rarity, c.Traits.Level),
+		fairValueKey(c.Symbol, c.Traits.Rarity, ""),
+	} {
+		// A token relisted at a new price replaces its previous listing.
+		group := f.groups[key][:0]
+		for _, existing := range f.groups[key] {
+			if existing.TokenID != c.TokenID || existing.At.After(c.At) {
+				group = append(group, existing)
+			}
+		}
+
+		group = append(group, c)
+		sort.Slice(group, func(i, j int) bool { return group[i].At.After(group[j].At) })
+		if len(group) > MaxComparables {
+			group = group[:MaxComparables]
+		}
+
+		f.groups[key] = group
+	}
+}
+
+// estimate returns the median price of tokens similar to t, excluding the
+// token itself, and how many prices it is based on.
+func (f *fairValues) estimate(tokenID string, symbol coinbase.CryptoSymbol, t traits) (float64, int) {
+	for _, key := range []string{
+		fairValueKey(symbol, t.Rarity, t.Level),
+		fairValueKey(symbol, t.Rarity, ""),
+	} {
+		var prices []float64
+		for _, c := range f.groups[key] {
+			if c.TokenID != tokenID {
+				prices = append(prices, c.Price)
+			}
+		}
+
+		if len(prices) >= MinComparables {
+			return median(prices), len(prices)
+		}
+	}
+
+	return 0, 0
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// getTraits returns the rarity and level of the token, from the hero index
+// or the cache when possible since orders do not include metadata.
+func (w *Watcher) getTraits(order imxapi.Order) (traits, bool) {
+	sell := order.Sell.GetData()
+	if w.index != nil && sell.GetTokenAddress() == data.BitVerseCollections[data.CollectionHero].Address {
+		if e, ok := w.index.Get(sell.GetTokenId()); ok {
+			return traits{Rarity: e.Rarity(), Level: e.Level()}, true
+		}
+	}
+
+	key := sell.GetTokenAddress() + "/" + sell.GetTokenId()
+	if t, ok := w.traits.get(key); ok {
+		return t, true
+	}
+
+	asset, err := w.getAsset(order)
+	if err != nil {
+		return traits{}, false
+	}
+
+	metadata := asset.GetMetadata()
+	t := traits{Rarity: getMetadataString(metadata, w.config.collection().RarityKey())}
+	if v, ok := metadata[w.config.collection().LevelKey()]; ok {
+		t.Level = fmt.Sprint(v)
+	}
+
+	w.traits.put(key, t)
+	return t, true
+}
+
+func (w *Watcher) newComparable(order imxapi.Order, timestamp string) (comparable, bool) {
+	t, ok := w.getTraits(order)
+	if !ok {
+		return comparable{}, false
+	}
+
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		at = time.Now()
+	}
+
+	return comparable{
+		TokenID: order.Sell.Data.GetTokenId(),
+		Price:   w.getPrice(order),
+		Symbol:  w.getCryptoSymbol(order.GetBuy().Type),
+		At:      at,
+		Traits:  t,
+	}, true
+}
+
+// refreshFairValues swaps in finished fair values and starts loading new ones
+// every FairValueRefresh. Loading can take thousands of requests, so it runs
+// in the background. Until the first load finishes, new listings are valued
+// against the listings seen so far only.
+func (w *Watcher) refreshFairValues(cfg *orders.ListOrdersConfig) {
+	select {
+	case values := <-w.fairValuesLoad:
+		// Keep the listings the checks saw while loading.
+		if w.fairValues != nil {
+			for _, group := range w.fairValues.groups {
+				for _, c := range group {
+					if c.At.After(values.loadedAt) {
+						values.add(c)
+					}
+				}
+			}
+		}
+
+		w.fairValues = values
+		w.fairValuesLoad = nil
+		log.Infof("loaded fair values of %v groups for %v", len(values.groups), w)
+	default:
+	}
+
+	if w.fairValues == nil {
+		w.fairValues = &fairValues{groups: make(map[string][]comparable)}
+	}
+
+	if w.fairValuesLoad == nil && time.Since(w.fairValues.loadedAt) > FairValueRefresh {
+		ch := make(chan *fairValues, 1)
+		w.fairValuesLoad = ch
+
+		load := *cfg
+		go func() { ch <- w.loadFairValues(&load) }()
+	}
+}
+
+// loadFairValues rebuilds the comparables from the sales in FairValueWindow
+// and every active listing matching the watcher's filters.
+func (w *Watcher) loadFairValues(cfg *orders.ListOrdersConfig) *fairValues {
+	values := &fairValues{groups: make(map[string][]comparable), loadedAt: time.Now()}
+
+	sales := *cfg
+	sales.Status = "filled"
+	sales.OrderBy = "updated_at"
+	sales.MinTimestamp = ""
+	sales.UpdatedMinTimestamp = ""
+	w.eachPage(&sales, time.Now().Add(-FairValueWindow), true, func(order imxapi.Order) {
+		if c, ok := w.newComparable(order, order.GetUpdatedTimestamp()); ok {
+			values.add(c)
+		}
+	})
+
+	listings := *cfg
+	listings.Status = "active"
+	listings.OrderBy = "created_at"
+	listings.MinTimestamp = ""
+	listings.UpdatedMinTimestamp = ""
+	w.eachPage(&listings, time.Time{}, false, func(order imxapi.Order) {
+		if c, ok := w.newComparable(order, order.GetTimestamp()); ok {
+			values.add(c)
+		}
+	})
+
+	return values
+}
+
+// eachPage pages through every order after since, by update time when
+// updated is set, up to MaxBaselinePages pages.
+func (w *Watcher) eachPage(cfg *orders.ListOrdersConfig, since time.Time, updated bool, fn func(order imxapi.Order)) {
+	for page := 0; page < MaxBaselinePages; page++ {
+		if updated {
+			cfg.UpdatedMinTimestamp = since.UTC().Format(time.RFC3339)
+		} else {
+			cfg.MinTimestamp = since.UTC().Format(time.RFC3339)
+		}
+
+		result, err := w.clients.OrdersClient.ListOrders(context.Background(), cfg)
+		if err != nil {
+			log.Errorf("could not load orders for %v: %v", w, err)
+			return
+		}
+
+		for _, order := range result {
+			timestamp := order.GetTimestamp()
+			if updated {
+				timestamp = order.GetUpdatedTimestamp()
+			}
+			if t, err := time.Parse(time.RFC3339, timestamp); err == nil && t.After(since) {
+				since = t
+			}
+
+			fn(order)
+		}
+
+		if len(result) < cfg.PageSize {
+			return
+		}
+	}
+
+	log.Warnf("watcher %v reached the %v page limit loading orders", w, MaxBaselinePages)
+}
+
+// checkUnderpriced compares every new listing with the fair value of similar
+// tokens and notifies when it is at least MinDiscountPct below.
+func (w *Watcher) checkUnderpriced(cfg *orders.ListOrdersConfig) {
+	w.refreshFairValues(cfg)
+
+	w.eachNewOrder(cfg, func(order imxapi.Order, l listing) {
+		c, ok := w.newComparable(order, order.GetTimestamp())
+		if !ok {
+			return
+		}
+
+		fair, n := w.fairValues.estimate(l.TokenID, l.CryptoSymbol, c.Traits)
+		w.fairValues.add(c)
+		if fair <= 0 {
+			log.Debugf("not enough comparables to value #%v for %v", l.TokenID, w)
+			return
+		}
+
+		discount := (fair - l.CryptoPrice) / fair * 100
+		if discount < w.config.MinDiscountPct {
+			return
+		}
+
+		if w.config.Threshold > 0 && l.FiatPrice > w.config.Threshold {
+			return
+		}
+
+		l.Rarity = c.Traits.Rarity
+		level := c.Traits.Level
+		if level == "" {
+			level = "(Unknown)"
+		}
+
+		fairStr := fmt.Sprintf("%f %s", fair, l.CryptoSymbol)
+		log.Infof("#%v listed for %v %v, %0.1f%% under fair value %v", l.TokenID, l.CryptoPrice, l.CryptoSymbol, discount, fairStr)
+		w.notify(l, &discordgo.MessageSend{
+			Content: fmt.Sprintf(UnderpricedTemplate, discount, l.Name, l.FiatPriceStr, fairStr, n, l.Rarity, level, l.TokenID, l.URLs.Immutascan, l.URLs.ImmutableMarket),
+		})
+	})
+}
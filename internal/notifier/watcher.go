@@ -6,14 +6,14 @@ import (
 	"fmt"
 	"math"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/deadloct/bitverse-nft-bot/internal/api"
-	"github.com/deadloct/bitverse-nft-bot/internal/config"
-	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
 	"github.com/deadloct/bitverse-nft-bot/internal/handlers"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
 	"github.com/deadloct/immutablex-go-lib/coinbase"
 	"github.com/deadloct/immutablex-go-lib/orders"
 	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
@@ -21,8 +21,17 @@ import (
 )
 
 const (
-	CheckInterval = 10 * time.Second
-	DMTemplate    = `New cheapest NFT:
+	CheckInterval     = 10 * time.Second
+	NewOrdersPageSize = 50
+	MaxNewOrdersPages = 10
+	DMTemplate        = `New cheapest NFT:
+- name: %v
+- price: %v
+- rarity: %v
+- token id: %v
+- immutascan: %v
+- immutable market: %v`
+	ListingTemplate = `New listing:
 - name: %v
 - price: %v
 - rarity: %v
@@ -31,57 +40,57 @@ const (
 - immutable market: %v`
 )
 
-type Seen struct {
-	ID    string
-	Price float64
-}
-
 type Watcher struct {
-	clients     *api.ClientsManager
-	coinbase    *coinbase.CoinbaseClient
-	rarity      []string
-	sender      *DiscordSender
-	seens       []Seen
-	started     bool
-	stop        chan struct{}
-	userSubs    []string
-	channelSubs []string
-	threshold   float64
-}
-
-func NewWatcher(cm *api.ClientsManager, session *discordgo.Session, rarity []string, priceThreshold float64) *Watcher {
-	userIDs := strings.Split(config.GetenvStr("USER_SUBSCRIPTIONS"), ",")
-	var userSubs []string
-	for _, id := range userIDs {
-		if id != "" {
-			userSubs = append(userSubs, id)
-		}
-	}
-
-	channelIDs := strings.Split(config.GetenvStr("CHANNEL_SUBSCRIPTIONS"), ",")
-	var channelSubs []string
-	for _, id := range channelIDs {
-		if id != "" {
-			channelSubs = append(channelSubs, id)
-		}
-	}
+	clients        *api.ClientsManager
+	config         WatcherConfig
+	fairValues     *fairValues
+	fairValuesLoad chan *fairValues
+	index          *index.Index
+	seen           SeenStore
+	sender         *DiscordSender
+	spot           api.SpotPriceClient
+	prices         map[string]listedPrice
+	baseline       chan priceBaseline
+	pricesAt       time.Time
+	since          time.Time
+	started        bool
+	stop           chan struct{}
+	done           chan struct{}
+	subs           *Subscriptions
+	traits         *traitsCache
+	mu             sync.Mutex // guards started, stop and done
+}
 
+// NewWatcher creates a watcher. idx is the hero index used to look up the
+// traits of listed heroes and may be nil, in which case assets are fetched.
+func NewWatcher(
+	cm *api.ClientsManager,
+	session discord.Transport,
+	cfg WatcherConfig,
+	seen SeenStore,
+	subs *Subscriptions,
+	idx *index.Index,
+) *Watcher {
 	return &Watcher{
-		clients:     cm,
-		coinbase:    coinbase.GetCoinbaseClientInstance(),
-		rarity:      rarity,
-		sender:      NewDiscordSender(session),
-		userSubs:    userSubs,
-		channelSubs: channelSubs,
-		threshold:   priceThreshold,
+		clients: cm,
+		config:  cfg,
+		index:   idx,
+		seen:    seen,
+		sender:  NewDiscordSender(session),
+		spot:    cm.SpotPriceClient,
+		subs:    subs,
+		traits:  newTraitsCache(),
 	}
 }
 
 func (w *Watcher) Start() error {
-	log.Infof("starting watcher %v/$%v", w.rarity, w.threshold)
+	log.Infof("starting watcher %v", w)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
 	if w.started {
-		log.Infof("watcher %v/$%v already started", w.rarity, w.threshold)
+		log.Infof("watcher %v already started", w)
 		return nil
 	}
 
@@ -90,46 +99,115 @@ func (w *Watcher) Start() error {
 	}
 
 	w.started = true
-	log.Infof("watcher %v/$%v started", w.rarity, w.threshold)
+	log.Infof("watcher %v started", w)
 	return nil
 }
 
 func (w *Watcher) Stop() {
-	log.Infof("stopping watcher %v/$%v", w.rarity, w.threshold)
+	log.Infof("stopping watcher %v", w)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.started {
+		log.Infof("watcher %v already stopped", w)
+		return
+	}
+
+	// Wait for a check in progress, so a quick Stop and Start never has two
+	// goroutines polling with the same state.
 	close(w.stop)
+	<-w.done
+	w.started = false
+}
+
+func (w *Watcher) Started() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.started
+}
+
+func (w *Watcher) Config() WatcherConfig {
+	return w.config
+}
+
+// visibleIn reports whether guild members may see and subscribe to the
+// watcher. Watchers from the watchers file are visible everywhere.
+func (w *Watcher) visibleIn(guildID string) bool {
+	return w.config.OwnerID == "" || w.config.GuildID == guildID
+}
+
+func (w *Watcher) String() string {
+	return fmt.Sprintf("%v %v/%v", w.config.Name, w.config.Rarity, handlers.FormatPrice(w.config.Threshold, w.config.Currency))
 }
 
+// loop starts polling in the background, the first check included, so
+// starting a watcher never waits on the network.
 func (w *Watcher) loop() error {
-	w.stop = make(chan struct{}, 1)
-	rarityJSON, err := json.Marshal(w.rarity)
+	metadataJSON, err := json.Marshal(w.config.sellMetadata())
 	if err != nil {
-		log.Errorf("could not decode rarity: %v", err)
+		log.Errorf("could not encode sell metadata: %v", err)
 		return err
 	}
 
 	cfg := &orders.ListOrdersConfig{
-		BuyTokenType:     handlers.TokenTypeETH,
+		BuyTokenType:     w.config.buyTokenType(),
 		PageSize:         1,
-		SellTokenAddress: data.BitVerseCollections["hero"].Address,
+		SellTokenAddress: w.config.collection().Address,
 		Status:           "active",
 		OrderBy:          "buy_quantity_with_fees",
 		Direction:        "asc",
-		SellMetadata:     fmt.Sprintf(`{"Rarity": %s}`, rarityJSON),
+		SellMetadata:     string(metadataJSON),
+	}
+
+	check := w.checkCheapest
+	switch w.config.Mode {
+	case ModeListings:
+		cfg.PageSize = NewOrdersPageSize
+		cfg.OrderBy = "created_at"
+		check = w.checkListings
+	case ModeSales:
+		cfg.PageSize = NewOrdersPageSize
+		cfg.Status = "filled"
+		cfg.OrderBy = "updated_at"
+		check = w.checkSales
+	case ModePriceDrops:
+		cfg.PageSize = NewOrdersPageSize
+		cfg.OrderBy = "updated_at"
+		check = w.checkPriceDrops
+	case ModeUnderpriced:
+		cfg.PageSize = NewOrdersPageSize
+		cfg.OrderBy = "created_at"
+		check = w.checkUnderpriced
+	}
+
+	// Only orders after startup are announced, older ones were either
+	// already sent or are too stale to be interesting.
+	if w.config.Mode != ModeCheapest && w.since.IsZero() {
+		w.since = time.Now().Add(-CheckInterval)
 	}
 
-	w.check(cfg) // first run on startup
+	stop := make(chan struct{}, 1)
+	done := make(chan struct{})
+	w.stop = stop
+	w.done = done
 
-	ticker := time.NewTicker(CheckInterval)
 	go func() {
+		defer close(done)
+
+		w.run(check, cfg) // first run on startup
+
+		ticker := time.NewTicker(CheckInterval)
 		for {
 			select {
-			case <-w.stop:
-				log.Infof("received stop in watcher %v/$%v", w.rarity, w.threshold)
+			case <-stop:
+				log.Infof("received stop in watcher %v", w)
 				ticker.Stop()
 				return
 			case <-ticker.C:
-				log.Debugf("checking watcher %v/$%v", w.rarity, w.threshold)
-				w.check(cfg)
+				log.Debugf("checking watcher %v", w)
+				w.run(check, cfg)
 			}
 		}
 	}()
@@ -137,22 +215,150 @@ func (w *Watcher) loop() error {
 	return nil
 }
 
-func (w *Watcher) check(cfg *orders.ListOrdersConfig) {
+// run does one check and saves every listing it marked as seen at once.
+func (w *Watcher) run(check func(cfg *orders.ListOrdersConfig), cfg *orders.ListOrdersConfig) {
+	check(cfg)
+
+	if err := w.seen.Flush(); err != nil {
+		log.Errorf("could not persist seen listings of %v: %v", w, err)
+	}
+}
+
+// checkCheapest notifies when the single cheapest listing is under the
+// threshold.
+func (w *Watcher) checkCheapest(cfg *orders.ListOrdersConfig) {
 	result, err := w.clients.OrdersClient.ListOrders(context.Background(), cfg)
 	if err != nil {
 		log.Error(err)
 	}
 
 	if len(result) == 0 {
-		log.Infof("no results returned for %v/%v", w.rarity, w.threshold)
+		log.Infof("no results returned for %v", w)
 		return
 	}
 
-	order := result[0]
+	l := w.newListing(result[0])
+	if l.FiatPrice <= w.config.Threshold && !w.seen.AlreadySeen(w.config.Name, l.TokenID, l.CryptoPrice) {
+		log.Infof("new cheapest (#%v) with fees: %v (%v %v)", l.TokenID, l.FiatPriceStr, l.CryptoPrice, l.CryptoSymbol)
+		w.notify(l, &discordgo.MessageSend{
+			Content: fmt.Sprintf(DMTemplate, l.Name, l.FiatPriceStr, w.config.Rarity, l.TokenID, l.URLs.Immutascan, l.URLs.ImmutableMarket),
+		})
+
+		if err := w.seen.Add(w.config.Name, l.TokenID, l.CryptoPrice); err != nil {
+			log.Errorf("could not persist seen listing %v: %v", l.TokenID, err)
+		}
+		log.Infof("adding %v to seen, no notifications should be sent again", l.TokenID)
+	}
+}
+
+// checkListings notifies about every listing created since the previous poll
+// that is under the threshold, if any.
+func (w *Watcher) checkListings(cfg *orders.ListOrdersConfig) {
+	w.eachNewOrder(cfg, func(order imxapi.Order, l listing) {
+		if w.config.Threshold > 0 && l.FiatPrice > w.config.Threshold {
+			return
+		}
+
+		if len(w.config.Rarity) != 1 {
+			if asset, err := w.getAsset(order); err == nil {
+				l.Rarity = getMetadataString(asset.GetMetadata(), w.config.collection().RarityKey())
+			}
+		}
+
+		log.Infof("new listing %v (#%v) with fees: %v (%v %v)", order.OrderId, l.TokenID, l.FiatPriceStr, l.CryptoPrice, l.CryptoSymbol)
+		w.notify(l, &discordgo.MessageSend{
+			Content: fmt.Sprintf(ListingTemplate, l.Name, l.FiatPriceStr, l.Rarity, l.TokenID, l.URLs.Immutascan, l.URLs.ImmutableMarket),
+		})
+	})
+}
+
+// eachNewOrder pages through the orders created since the previous poll, or
+// updated since then for sales, and calls fn once for each order.
+func (w *Watcher) eachNewOrder(cfg *orders.ListOrdersConfig, fn func(order imxapi.Order, l listing)) {
+	for page := 0; page < MaxNewOrdersPages; page++ {
+		since := w.since.UTC().Format(time.RFC3339)
+		if w.config.Mode == ModeSales || w.config.Mode == ModePriceDrops {
+			cfg.UpdatedMinTimestamp = since
+		} else {
+			cfg.MinTimestamp = since
+		}
+
+		result, err := w.clients.OrdersClient.ListOrders(context.Background(), cfg)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+
+		log.Debugf("%v orders since %v for %v", len(result), since, w)
+		for _, order := range result {
+			timestamp := order.GetTimestamp()
+			if w.config.Mode == ModeSales || w.config.Mode == ModePriceDrops {
+				timestamp = order.GetUpdatedTimestamp()
+			}
+			if t, err := time.Parse(time.RFC3339, timestamp); err == nil && t.After(w.since) {
+				w.since = t
+			}
+
+			orderID := fmt.Sprint(order.OrderId)
+			l := w.newListing(order)
+			if w.seen.AlreadySeen(w.config.Name, orderID, l.CryptoPrice) {
+				continue
+			}
+
+			fn(order, l)
+
+			if err := w.seen.Add(w.config.Name, orderID, l.CryptoPrice); err != nil {
+				log.Errorf("could not persist seen order %v: %v", orderID, err)
+			}
+		}
+
+		// A short page means everything since the last poll was returned,
+		// otherwise fetch the next page starting at the newest order seen.
+		if len(result) < cfg.PageSize {
+			return
+		}
+	}
+
+	log.Warnf("watcher %v reached the %v page limit, some orders may have been skipped", w, MaxNewOrdersPages)
+}
+
+func (w *Watcher) notify(l listing, msg *discordgo.MessageSend) {
+	recipients := w.recipients()
+	for _, id := range recipients.Users {
+		if err := w.sender.SendDMMessage(id, msg); err != nil {
+			log.Error(err)
+			continue
+		}
+
+		log.Infof("sent notification about item %v (%v) priced at %v to user %v", l.Name, l.TokenID, l.FiatPriceStr, id)
+	}
+
+	for _, id := range recipients.Channels {
+		if err := w.sender.SendChannelMessage(id, msg); err != nil {
+			log.Error(err)
+			continue
+		}
+
+		log.Infof("sent notification about item %v (%v) priced at %v to channel %v", l.Name, l.TokenID, l.FiatPriceStr, id)
+	}
+}
+
+// listing holds the details of an order that go into notifications.
+type listing struct {
+	Name         string
+	TokenID      string
+	Rarity       string
+	URLs         handlers.OrderURLs
+	CryptoPrice  float64
+	CryptoSymbol coinbase.CryptoSymbol
+	FiatPrice    float64
+	FiatPriceStr string
+}
+
+func (w *Watcher) newListing(order imxapi.Order) listing {
 	data := order.Sell.GetData()
 	collection := data.GetTokenAddress()
 	tokenID := data.GetTokenId()
-	urls := handlers.GetOrderURLs(collection, tokenID)
 	name := order.Sell.Data.Properties.GetName()
 	if name == "" {
 		name = "Item " + tokenID
@@ -160,34 +366,63 @@ func (w *Watcher) check(cfg *orders.ListOrdersConfig) {
 
 	cryptoPrice := w.getPrice(order)
 	cryptoSymbol := w.getCryptoSymbol(order.GetBuy().Type)
-	fiatPrice := cryptoPrice * w.coinbase.RetrieveSpotPrice(cryptoSymbol, coinbase.FiatUSD)
+	fiatPrice := cryptoPrice * w.spot.RetrieveSpotPrice(cryptoSymbol, w.config.Currency)
 
-	if fiatPrice <= w.threshold && !w.alreadySeen(tokenID, cryptoPrice) {
-		log.Infof("new cheapest (#%v) with fees: $%0.2f (%v %v)", tokenID, fiatPrice, cryptoPrice, cryptoSymbol)
+	rarity := "(Unknown)"
+	if len(w.config.Rarity) == 1 {
+		rarity = w.config.Rarity[0]
+	} else if len(w.config.Rarity) > 1 {
+		rarity = fmt.Sprint(w.config.Rarity)
+	}
 
-		priceStr := fmt.Sprintf("$%0.2f", fiatPrice)
-		msg := fmt.Sprintf(DMTemplate, name, priceStr, w.rarity, tokenID, urls.Immutascan, urls.ImmutableMarket)
-		for _, id := range w.userSubs {
-			if err := w.sender.SendDM(id, msg); err != nil {
-				log.Error(err)
-				continue
-			}
+	return listing{
+		Name:         name,
+		TokenID:      tokenID,
+		Rarity:       rarity,
+		URLs:         handlers.GetOrderURLs(collection, tokenID),
+		CryptoPrice:  cryptoPrice,
+		CryptoSymbol: cryptoSymbol,
+		FiatPrice:    fiatPrice,
+		FiatPriceStr: handlers.FormatPrice(fiatPrice, w.config.Currency),
+	}
+}
 
-			log.Infof("sent notification about item %v (%v) priced at %v to user %v", name, tokenID, priceStr, id)
-		}
+// getAsset fetches the asset being sold, since orders do not include its
+// metadata or current owner.
+func (w *Watcher) getAsset(order imxapi.Order) (*imxapi.Asset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-		for _, id := range w.channelSubs {
-			if err := w.sender.SendChannel(id, msg); err != nil {
-				log.Error(err)
-				continue
-			}
+	sell := order.Sell.GetData()
+	asset, err := w.clients.AssetsClient.GetAsset(ctx, sell.GetTokenAddress(), sell.GetTokenId(), false)
+	if err != nil {
+		log.Errorf("unable to retrieve asset %v: %v", sell.GetTokenId(), err)
+		return nil, err
+	}
 
-			log.Infof("sent notification about item %v (%v) priced at %v to channel %v", name, tokenID, priceStr, id)
-		}
+	return asset, nil
+}
 
-		w.seens = append(w.seens, Seen{ID: tokenID, Price: cryptoPrice})
-		log.Infof("adding %v to seen, no notifications should be sent again", tokenID)
+func getMetadataString(metadata map[string]interface{}, key string) string {
+	if v, ok := metadata[key].(string); ok && v != "" {
+		return v
 	}
+
+	return "(Unknown)"
+}
+
+// recipients merges the static recipients from the watcher definition with
+// the current subscriptions, which may change while the watcher runs.
+func (w *Watcher) recipients() Recipients {
+	r := w.subs.Recipients(w.config.Name)
+	for _, id := range w.config.Users {
+		r.Users = appendUnique(r.Users, id)
+	}
+	for _, id := range w.config.Channels {
+		r.Channels = appendUnique(r.Channels, id)
+	}
+
+	return r
 }
 
 func (w *Watcher) getPrice(order imxapi.Order) float64 {
@@ -210,13 +445,3 @@ func (w *Watcher) getCryptoSymbol(str string) coinbase.CryptoSymbol {
 
 	return coinbase.CryptoSymbol(str)
 }
-
-func (w *Watcher) alreadySeen(id string, price float64) bool {
-	for _, s := range w.seens {
-		if s.ID == id && s.Price == price {
-			return true
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/api/fake"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	imxapi "github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// blockingOrders holds every ListOrders call until release is closed.
+type blockingOrders struct {
+	calls   chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingOrders) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]imxapi.Order, error) {
+	b.calls <- struct{}{}
+	<-b.release
+	return nil, nil
+}
+
+func newTestSubscriptions(t *testing.T) *Subscriptions {
+	t.Helper()
+
+	subs, err := NewSubscriptions(filepath.Join(t.TempDir(), DefaultSubscriptionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return subs
+}
+
+func TestWatcherStopWaitsForCheck(t *testing.T) {
+	list := &blockingOrders{calls: make(chan struct{}, 10), release: make(chan struct{})}
+	cm := fake.NewClientsManager(nil, nil, nil)
+	cm.OrdersClient = list
+
+	cfg := WatcherConfig{Name: "common", Collection: data.CollectionHero, Threshold: 1}
+	w := NewWatcher(cm, discord.NewRecorder(), cfg, NewMemorySeenStore(DefaultSeenTTL), newTestSubscriptions(t), nil)
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-list.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first check never ran")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while a check was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(list.release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop never returned after the check finished")
+	}
+
+	if w.Started() {
+		t.Error("watcher still started after Stop")
+	}
+
+	// Restarting runs a new first check on the new goroutine only.
+	if err := w.Start(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-list.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the check after restarting never ran")
+	}
+	w.Stop()
+}
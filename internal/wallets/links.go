@@ -0,0 +1,61 @@
+package wallets
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/deadloct/bitverse-nft-bot/internal/lib/jsonfile"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultLinksFile = "wallets.json"
+
+// Links maps Discord user IDs to the IMX wallet address they linked. It is
+// saved to disk on every change.
+type Links struct {
+	path  string
+	links map[string]string
+	mu    sync.Mutex
+}
+
+// NewLinks loads the wallet links saved at path. An empty path keeps them in
+// memory only.
+func NewLinks(path string) (*Links, error) {
+	l := &Links{path: path, links: make(map[string]string)}
+	if err := jsonfile.Load(path, &l.links); err != nil {
+		return nil, err
+	}
+
+	log.Infof("loaded %v linked wallets from %v", len(l.links), path)
+	return l, nil
+}
+
+// Get returns the address linked to the user.
+func (l *Links) Get(userID string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	address, ok := l.links[userID]
+	return address, ok
+}
+
+func (l *Links) Link(userID, address string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.links[userID] = strings.ToLower(address)
+	return jsonfile.Save(l.path, l.links)
+}
+
+// Unlink removes the user's address, reporting whether one was linked.
+func (l *Links) Unlink(userID string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.links[userID]; !ok {
+		return false, nil
+	}
+
+	delete(l.links, userID)
+	return true, jsonfile.Save(l.path, l.links)
+}
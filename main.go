@@ -4,12 +4,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/deadloct/bitverse-nft-bot/internal/api"
+	"github.com/deadloct/bitverse-nft-bot/internal/api/cassette"
 	"github.com/deadloct/bitverse-nft-bot/internal/cmd"
 	"github.com/deadloct/bitverse-nft-bot/internal/config"
+	"github.com/deadloct/bitverse-nft-bot/internal/data"
+	"github.com/deadloct/bitverse-nft-bot/internal/discord"
+	"github.com/deadloct/bitverse-nft-bot/internal/history"
+	"github.com/deadloct/bitverse-nft-bot/internal/index"
 	"github.com/deadloct/bitverse-nft-bot/internal/notifier"
+	"github.com/deadloct/bitverse-nft-bot/internal/wallets"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -20,35 +27,142 @@ func main() {
 
 	config.LoadEnvFiles()
 
-	session, err := discordgo.New("Bot " + config.GetenvStr("AUTH_TOKEN"))
-	if err != nil {
+	collectionsFile := config.GetenvStr("COLLECTIONS_FILE")
+	if collectionsFile == "" {
+		collectionsFile = data.DefaultCollectionsFile
+	}
+	if err := data.LoadCollections(config.FilePath(collectionsFile)); err != nil {
 		log.Panic(err)
 	}
 
-	// Listen for server (guild) messages only
-	session.Identify.Intents = discordgo.IntentsGuildMessages
+	if len(os.Args) > 1 && os.Args[1] == CMDBacktest {
+		if err := backtest(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	cm := api.NewClientsManager()
 
+	// Record API traffic to a cassette, or replay one instead of the network
+	var replaying bool
+	if path := config.GetenvStr("CASSETTE_FILE"); path != "" {
+		c, err := cassette.Open(config.FilePath(path), config.GetenvStr("CASSETTE_MODE"))
+		if err != nil {
+			log.Panic(err)
+		}
+		defer c.Close()
+		cassette.Wrap(cm, c)
+		replaying = c.Replaying()
+	}
+
+	// Empty paths keep the subscriptions, wallet links, hero index and
+	// runtime watchers in memory.
+	var (
+		session      discord.Transport
+		seen         notifier.SeenStore
+		historyStore history.Store
+
+		subsPath, linksPath, heroesPath, watchersPath string
+	)
+	if replaying {
+		// A replay must neither message anyone nor touch the saved state, so
+		// notifications are only recorded and every order starts unseen.
+		log.Info("replaying a cassette, notifications are logged instead of sent")
+		session = discord.NewRecorder()
+		seen = notifier.NewMemorySeenStore(notifier.DefaultSeenTTL)
+		historyStore = history.NewMemoryStore(history.DefaultRetention)
+	} else {
+		subsPath = config.DataPath(notifier.DefaultSubscriptionsFile)
+		linksPath = config.DataPath(wallets.DefaultLinksFile)
+		heroesPath = config.DataPath(index.DefaultHeroesFile)
+		watchersPath = config.DataPath(notifier.DefaultRuntimeWatchersFile)
+
+		dg, err := discordgo.New("Bot " + config.GetenvStr("AUTH_TOKEN"))
+		if err != nil {
+			log.Panic(err)
+		}
+
+		// Listen for server (guild) messages only
+		dg.Identify.Intents = discordgo.IntentsGuildMessages
+		session = dg
+
+		if seen, err = notifier.NewFileSeenStore(config.DataPath(notifier.DefaultSeenFile), notifier.DefaultSeenTTL); err != nil {
+			log.Panic(err)
+		}
+
+		if historyStore, err = history.NewFileStore(config.DataPath(history.DefaultHistoryFile), history.DefaultRetention); err != nil {
+			log.Panic(err)
+		}
+	}
+
+	subs, err := notifier.NewSubscriptions(subsPath)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	links, err := wallets.NewLinks(linksPath)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	heroIndex := index.New(cm, data.BitVerseCollections[data.CollectionHero], heroesPath)
+	watchers := notifier.NewManager(cm, session, seen, subs, heroIndex, watchersPath)
+
 	// Slash command controller
-	slash := cmd.NewSlashCommands(cm, session)
+	slash := cmd.NewSlashCommands(cm, session, watchers, subs, historyStore, heroIndex, links)
 	if err := slash.Start(); err != nil {
 		log.Panic(err)
 	}
 	defer slash.Stop()
 
+	// Hero index for autocomplete, crawled once the clients are started
+	if err := heroIndex.Start(); err != nil {
+		log.Panic(err)
+	}
+	defer heroIndex.Stop()
+
 	// Loop price watchers
-	commonWatcher := notifier.NewWatcher(cm, session, []string{"Common"}, 250.0)
-	commonWatcher.Start()
-	defer commonWatcher.Stop()
+	watcherConfigs, err := notifier.LoadWatcherConfigs()
+	if err != nil {
+		log.Panic(err)
+	}
 
-	rareWatcher := notifier.NewWatcher(cm, session, []string{"Rare"}, 550.0)
-	rareWatcher.Start()
-	defer rareWatcher.Stop()
+	// Subscribe the env recipients first so the first checks already have
+	// someone to notify.
+	var watcherNames []string
+	for _, cfg := range watcherConfigs {
+		watcherNames = append(watcherNames, cfg.Name)
+	}
+	if err := subs.ImportEnv(watcherNames); err != nil {
+		log.Error(err)
+	}
+
+	for _, cfg := range watcherConfigs {
+		if _, err := watchers.Add(cfg); err != nil {
+			log.Panic(err)
+		}
+	}
+	defer watchers.Stop()
+
+	if err := watchers.Load(); err != nil {
+		log.Panic(err)
+	}
+
+	// Record floor prices for /history
+	sampleInterval := notifier.DefaultSampleInterval
+	if v := config.GetenvStr("SAMPLE_INTERVAL"); v != "" {
+		if sampleInterval, err = time.ParseDuration(v); err != nil {
+			log.Panic(err)
+		}
+		if sampleInterval <= 0 {
+			log.Panicf("SAMPLE_INTERVAL must be positive, got %v", v)
+		}
+	}
 
-	epicLegMythWatcher := notifier.NewWatcher(cm, session, []string{"Epic", "Legendary", "Mythic"}, 800.0)
-	epicLegMythWatcher.Start()
-	defer epicLegMythWatcher.Stop()
+	sampler := notifier.NewSampler(cm, historyStore, sampleInterval)
+	sampler.Start()
+	defer sampler.Stop()
 
 	log.Info("Bot is now running. Press CTRL-C to exit.")
 	sc := make(chan os.Signal, 1)